@@ -0,0 +1,131 @@
+package graphql
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// maxRequestSize limits the size of a GraphQL request body.
+const maxRequestSize = 1 << 20
+
+// Handler serves GraphQL requests over HTTP.
+type Handler struct {
+	schema graphql.Schema
+	limits Limits
+}
+
+// NewHandler creates a new instance of Handler.
+func NewHandler(service usecases.Subcription, limits Limits) (*Handler, error) {
+	schema, err := NewSchema(service)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{schema: schema, limits: limits}, nil
+}
+
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// @Summary GraphQL endpoint
+// @ID graphql
+// @Description Query subscriptions, users and cost aggregates or run subscription mutations. POST takes {"query", "operationName", "variables"}; GET takes the same as query parameters and only runs queries. Queries deeper or more complex than the configured limits are rejected.
+// @Tags graphql
+// @Accept  json
+// @Produce json
+// @Param query query string false "GraphQL query (GET)"
+// @Success 200 {object} object "GraphQL result"
+// @Failure 400 {object} object "Invalid request or limits exceeded"
+// @Router /graphql [post]
+func (h *Handler) serveGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeErrors(w, http.StatusBadRequest, "error while decoding variables: "+err.Error())
+				return
+			}
+		}
+		if isMutation(req.Query, req.OperationName) {
+			writeErrors(w, http.StatusMethodNotAllowed, "mutations are only allowed over POST")
+			return
+		}
+	} else {
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize)).Decode(&req); err != nil {
+			writeErrors(w, http.StatusBadRequest, "error while decoding json: "+err.Error())
+			return
+		}
+	}
+	if req.Query == "" {
+		writeErrors(w, http.StatusBadRequest, "query is required")
+		return
+	}
+	if err := h.limits.check(req.Query); err != nil {
+		slog.WarnContext(r.Context(), "graphql query rejected", "error", err)
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        r.Context(),
+	})
+	if result.HasErrors() {
+		slog.WarnContext(r.Context(), "graphql request finished with errors", "errors", len(result.Errors))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (h *Handler) WithGraphQLHandlers(r chi.Router) {
+	r.Get("/graphql", h.serveGraphQL)
+	r.Post("/graphql", h.serveGraphQL)
+}
+
+// isMutation reports whether the operation that would run is a mutation.
+func isMutation(query, operationName string) bool {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return false
+	}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" || (op.Name != nil && op.Name.Value == operationName) {
+			if op.Operation == ast.OperationTypeMutation {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func writeErrors(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, &graphql.Result{Errors: []gqlerrors.FormattedError{{Message: message}}})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to write graphql response", "error", err)
+	}
+}
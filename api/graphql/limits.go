@@ -0,0 +1,144 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// listMultiplier is the assumed size of a list field when estimating complexity: the
+// selection under a list is paid for once per element.
+const listMultiplier = 10
+
+// listFields are the fields returning lists; their selections are multiplied by listMultiplier.
+var listFields = map[string]bool{"subscriptions": true}
+
+// Limits bounds how expensive a single query may be. Zero disables a limit.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+	// MaxIntrospectionDepth bounds the depth of __schema and __type selections instead of
+	// MaxDepth, since the introspection query of GraphQL tools nests deeper than data queries.
+	MaxIntrospectionDepth int
+}
+
+// check parses the query and rejects it when an operation is nested deeper than MaxDepth,
+// an introspection selection deeper than MaxIntrospectionDepth, or its estimated complexity
+// exceeds MaxComplexity. Introspection fields count toward the complexity once each, as the
+// schema they list is small and fixed. Syntax errors are left to the executor, which reports
+// them in the usual format.
+func (l Limits) check(query string) error {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return nil
+	}
+
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok && f.Name != nil {
+			fragments[f.Name.Value] = f
+		}
+	}
+	a := analyzer{fragments: fragments, costs: make(map[fragmentKey]cost)}
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		c := a.selectionSet(op.SelectionSet, map[string]bool{}, false)
+		if l.MaxDepth > 0 && c.depth > l.MaxDepth {
+			return fmt.Errorf("query depth %d exceeds the limit of %d", c.depth, l.MaxDepth)
+		}
+		if l.MaxIntrospectionDepth > 0 && c.introspectionDepth > l.MaxIntrospectionDepth {
+			return fmt.Errorf("introspection depth %d exceeds the limit of %d", c.introspectionDepth, l.MaxIntrospectionDepth)
+		}
+		if l.MaxComplexity > 0 && c.complexity > l.MaxComplexity {
+			return fmt.Errorf("query complexity %d exceeds the limit of %d", c.complexity, l.MaxComplexity)
+		}
+	}
+	return nil
+}
+
+// maxComplexity caps the estimate so that deeply multiplied queries can't overflow it.
+const maxComplexity = 1 << 40
+
+type analyzer struct {
+	fragments map[string]*ast.FragmentDefinition
+	// costs caches the cost of every fragment, so a fragment spread many times is walked
+	// once instead of once per spread, which would grow exponentially with nested spreads.
+	costs map[fragmentKey]cost
+}
+
+// fragmentKey tells apart the costs of a fragment inside and outside introspection, which
+// are counted differently.
+type fragmentKey struct {
+	name          string
+	introspection bool
+}
+
+// cost is what a selection set adds up to. introspectionDepth is the depth of its deepest
+// __schema or __type selection, which is not part of depth.
+type cost struct {
+	depth              int
+	introspectionDepth int
+	complexity         int
+}
+
+// isIntrospection reports whether the field is the root of an introspection selection.
+// __typename is a plain leaf and is counted like any other field.
+func isIntrospection(name string) bool {
+	return name == "__schema" || name == "__type"
+}
+
+// selectionSet returns the cost of a selection set. visiting holds the fragments on the
+// current path, so cyclic spreads, which validation rejects anyway, end. Within introspection
+// lists are not multiplied and all depth is introspection depth.
+func (a analyzer) selectionSet(set *ast.SelectionSet, visiting map[string]bool, introspection bool) cost {
+	var total cost
+	if set == nil {
+		return total
+	}
+	for _, sel := range set.Selections {
+		var c cost
+		switch s := sel.(type) {
+		case *ast.Field:
+			if s.Name == nil {
+				continue
+			}
+			if !introspection && isIntrospection(s.Name.Value) {
+				child := a.selectionSet(s.SelectionSet, visiting, true)
+				c = cost{introspectionDepth: child.depth + 1, complexity: child.complexity + 1}
+				break
+			}
+			child := a.selectionSet(s.SelectionSet, visiting, introspection)
+			if !introspection && listFields[s.Name.Value] {
+				child.complexity = min(child.complexity*listMultiplier, maxComplexity)
+			}
+			c = cost{depth: child.depth + 1, introspectionDepth: child.introspectionDepth, complexity: child.complexity + 1}
+		case *ast.InlineFragment:
+			c = a.selectionSet(s.SelectionSet, visiting, introspection)
+		case *ast.FragmentSpread:
+			if s.Name == nil || visiting[s.Name.Value] {
+				continue
+			}
+			key := fragmentKey{name: s.Name.Value, introspection: introspection}
+			if cached, ok := a.costs[key]; ok {
+				c = cached
+				break
+			}
+			fragment, ok := a.fragments[s.Name.Value]
+			if !ok {
+				continue
+			}
+			visiting[s.Name.Value] = true
+			c = a.selectionSet(fragment.SelectionSet, visiting, introspection)
+			delete(visiting, s.Name.Value)
+			a.costs[key] = c
+		}
+		total.depth = max(total.depth, c.depth)
+		total.introspectionDepth = max(total.introspectionDepth, c.introspectionDepth)
+		total.complexity = min(total.complexity+c.complexity, maxComplexity)
+	}
+	return total
+}
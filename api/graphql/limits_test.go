@@ -0,0 +1,87 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// introspectionQuery is the query GraphQL tools send to learn the schema.
+const introspectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    types { ...FullType }
+    directives { name description locations args { ...InputValue } }
+  }
+}
+fragment FullType on __Type {
+  kind name description
+  fields(includeDeprecated: true) { name description args { ...InputValue } type { ...TypeRef } isDeprecated deprecationReason }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { ...TypeRef }
+}
+fragment InputValue on __InputValue { name description type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name ofType { kind name ofType { kind name ofType { kind name ofType {
+    kind name ofType { kind name ofType { kind name ofType { kind name } } } } } } }
+}`
+
+// nestedFragmentsQuery builds a query whose fragments each spread the next one twice, so
+// walking every spread separately costs 2^n.
+func nestedFragmentsQuery(n int) string {
+	var b strings.Builder
+	b.WriteString("{ subscriptions { ...F0 } }\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "fragment F%d on Subscription { ...F%d ...F%d }\n", i, i+1, i+1)
+	}
+	fmt.Fprintf(&b, "fragment F%d on Subscription { price }\n", n)
+	return b.String()
+}
+
+func TestLimitsCheck(t *testing.T) {
+	limits := Limits{MaxDepth: 4, MaxComplexity: 200, MaxIntrospectionDepth: 15}
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{"within limits", `{ subscriptions { serviceName price } }`, ""},
+		{"too deep", `{ user(id: "1") { subscriptions { a { b { c } } } } }`, "query depth 5 exceeds"},
+		{"list multiplied", `{ subscriptions { ` + strings.Repeat("price ", 20) + `} }`, "query complexity 201 exceeds"},
+		{"typename counted", `{ ` + strings.Repeat("__typename ", 201) + `}`, "query complexity 201 exceeds"},
+		{"tool introspection", introspectionQuery, ""},
+		{"deep introspection", `{ __schema { types { ` + strings.Repeat("fields { type { ", 8) + "name" +
+			strings.Repeat(" } }", 8) + ` } } }`, "introspection depth 19 exceeds"},
+		{"fragment cycle", `{ subscriptions { ...A } } fragment A on Subscription { price ...A }`, ""},
+		{"syntax error left to executor", `{ subscriptions {`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := limits.check(tt.query)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("got %v; want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLimitsCheckRejectsNestedFragmentsQuickly(t *testing.T) {
+	limits := Limits{MaxDepth: 8, MaxComplexity: 100, MaxIntrospectionDepth: 15}
+	query := nestedFragmentsQuery(60)
+
+	start := time.Now()
+	err := limits.check(query)
+	if err == nil || !strings.Contains(err.Error(), "query complexity") {
+		t.Fatalf("got %v; want the complexity limit exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("check took %v; want each fragment walked once", elapsed)
+	}
+}
@@ -0,0 +1,406 @@
+// Package graphql exposes subscriptions, cost aggregates and subscription mutations as a
+// GraphQL API backed by the same use cases as the REST handlers.
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/kasparovgs/subscription-aggregation-service/api/http/types"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+)
+
+const monthLayout = "01-2006"
+
+// NewSchema builds the schema. Dates are month precise and use the MM-YYYY format, as in
+// the REST API.
+func NewSchema(service usecases.Subcription) (graphql.Schema, error) {
+	r := resolver{service: service}
+
+	subscriptionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.Fields{
+			"subscriptionId": subscriptionField(graphql.NewNonNull(graphql.ID), func(s *domain.Subscription) any {
+				return s.SubscriptionID.String()
+			}),
+			"serviceName": subscriptionField(graphql.NewNonNull(graphql.String), func(s *domain.Subscription) any {
+				return s.ServiceName
+			}),
+			"price": subscriptionField(graphql.NewNonNull(graphql.Int), func(s *domain.Subscription) any {
+				return s.Price
+			}),
+			"userId": subscriptionField(graphql.NewNonNull(graphql.ID), func(s *domain.Subscription) any {
+				return s.UserID.String()
+			}),
+			"startDate": subscriptionField(graphql.NewNonNull(graphql.String), func(s *domain.Subscription) any {
+				return s.StartDate.Format(monthLayout)
+			}),
+			"endDate": subscriptionField(graphql.String, func(s *domain.Subscription) any {
+				if s.EndDate == nil {
+					return nil
+				}
+				return s.EndDate.Format(monthLayout)
+			}),
+			"billingPeriod": subscriptionField(graphql.NewNonNull(graphql.String), func(s *domain.Subscription) any {
+				return s.BillingPeriod
+			}),
+			"deletedAt": subscriptionField(graphql.DateTime, func(s *domain.Subscription) any {
+				if s.DeletedAt == nil {
+					return nil
+				}
+				return *s.DeletedAt
+			}),
+		},
+	})
+	subscriptionList := graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(subscriptionType)))
+
+	listArgs := graphql.FieldConfigArgument{
+		"serviceName":    {Type: graphql.String},
+		"price":          {Type: graphql.Int},
+		"startDate":      {Type: graphql.String, Description: "Subscriptions starting in this month or later (MM-YYYY)"},
+		"endDate":        {Type: graphql.String, Description: "Subscriptions ending in this month or earlier (MM-YYYY)"},
+		"includeDeleted": {Type: graphql.Boolean, DefaultValue: false},
+	}
+	totalCostArgs := graphql.FieldConfigArgument{
+		"startDate":      {Type: graphql.NewNonNull(graphql.String)},
+		"endDate":        {Type: graphql.NewNonNull(graphql.String)},
+		"serviceName":    {Type: graphql.String},
+		"includeDeleted": {Type: graphql.Boolean, DefaultValue: false},
+	}
+
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.Fields{
+			"userId": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.ID),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(uuid.UUID).String(), nil
+				},
+			},
+			"subscriptions": &graphql.Field{
+				Type:    subscriptionList,
+				Args:    listArgs,
+				Resolve: r.userSubscriptions,
+			},
+			"totalCost": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Int),
+				Args:    totalCostArgs,
+				Resolve: r.userTotalCost,
+			},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"subscription": &graphql.Field{
+				Type:    subscriptionType,
+				Args:    graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: r.subscription,
+			},
+			"subscriptions": &graphql.Field{
+				Type:    subscriptionList,
+				Args:    withUserID(listArgs),
+				Resolve: r.subscriptions,
+			},
+			"totalCost": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.Int),
+				Args:    withUserID(totalCostArgs),
+				Resolve: r.totalCost,
+			},
+			"user": &graphql.Field{
+				Type: graphql.NewNonNull(userType),
+				Args: graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return parseUUID(p.Args["id"])
+				},
+			},
+		},
+	})
+
+	createInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "CreateSubscriptionInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"serviceName":   {Type: graphql.NewNonNull(graphql.String)},
+			"price":         {Type: graphql.NewNonNull(graphql.Int)},
+			"userId":        {Type: graphql.NewNonNull(graphql.ID)},
+			"startDate":     {Type: graphql.NewNonNull(graphql.String)},
+			"endDate":       {Type: graphql.String},
+			"billingPeriod": {Type: graphql.String},
+		},
+	})
+	patchInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "PatchSubscriptionInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"serviceName":   {Type: graphql.String},
+			"price":         {Type: graphql.Int},
+			"endDate":       {Type: graphql.String},
+			"billingPeriod": {Type: graphql.String},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createSubscription": &graphql.Field{
+				Type:    graphql.NewNonNull(subscriptionType),
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(createInput)}},
+				Resolve: r.createSubscription,
+			},
+			"patchSubscription": &graphql.Field{
+				Type: graphql.NewNonNull(subscriptionType),
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.ID)},
+					"input": {Type: graphql.NewNonNull(patchInput)},
+				},
+				Resolve: r.patchSubscription,
+			},
+			"deleteSubscription": &graphql.Field{
+				Type:    graphql.NewNonNull(subscriptionType),
+				Args:    graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.ID)}},
+				Resolve: r.deleteSubscription,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+func subscriptionField(t graphql.Output, get func(*domain.Subscription) any) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return get(p.Source.(*domain.Subscription)), nil
+		},
+	}
+}
+
+func withUserID(args graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	out := graphql.FieldConfigArgument{"userId": {Type: graphql.ID}}
+	for name, arg := range args {
+		out[name] = arg
+	}
+	return out
+}
+
+type resolver struct {
+	service usecases.Subcription
+}
+
+func (r resolver) subscription(p graphql.ResolveParams) (any, error) {
+	subID, err := parseUUID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	subs, err := r.service.GetSubscriptionByID(p.Context, subID)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return subs, nil
+}
+
+func (r resolver) subscriptions(p graphql.ResolveParams) (any, error) {
+	var userID *uuid.UUID
+	if id, ok := p.Args["userId"]; ok {
+		parsed, err := parseUUID(id)
+		if err != nil {
+			return nil, err
+		}
+		userID = &parsed
+	}
+	return r.listSubscriptions(p, userID)
+}
+
+func (r resolver) userSubscriptions(p graphql.ResolveParams) (any, error) {
+	userID := p.Source.(uuid.UUID)
+	return r.listSubscriptions(p, &userID)
+}
+
+func (r resolver) listSubscriptions(p graphql.ResolveParams, userID *uuid.UUID) (any, error) {
+	filter := &domain.SubscriptionFilter{UserID: userID, IncludeDeleted: p.Args["includeDeleted"] == true}
+	if s, ok := p.Args["serviceName"].(string); ok {
+		filter.ServiceName = &s
+	}
+	if price, ok := p.Args["price"].(int); ok {
+		filter.Price = &price
+	}
+	if s, ok := p.Args["startDate"].(string); ok {
+		start, err := parseMonth(s, "startDate")
+		if err != nil {
+			return nil, err
+		}
+		filter.StartDate = &start
+	}
+	if s, ok := p.Args["endDate"].(string); ok {
+		end, err := parseMonth(s, "endDate")
+		if err != nil {
+			return nil, err
+		}
+		filter.EndDate = &end
+	}
+
+	list, err := r.service.GetListOfSubscriptions(p.Context, filter)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	result := make([]*domain.Subscription, len(list))
+	for i := range list {
+		result[i] = &list[i]
+	}
+	return result, nil
+}
+
+func (r resolver) totalCost(p graphql.ResolveParams) (any, error) {
+	var userID *uuid.UUID
+	if id, ok := p.Args["userId"]; ok {
+		parsed, err := parseUUID(id)
+		if err != nil {
+			return nil, err
+		}
+		userID = &parsed
+	}
+	return r.costOf(p, userID)
+}
+
+func (r resolver) userTotalCost(p graphql.ResolveParams) (any, error) {
+	userID := p.Source.(uuid.UUID)
+	return r.costOf(p, &userID)
+}
+
+func (r resolver) costOf(p graphql.ResolveParams, userID *uuid.UUID) (any, error) {
+	start, err := parseMonth(p.Args["startDate"].(string), "startDate")
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseMonth(p.Args["endDate"].(string), "endDate")
+	if err != nil {
+		return nil, err
+	}
+	filter := &domain.TotalCostFilter{
+		UserID:         userID,
+		StartDate:      start,
+		EndDate:        end,
+		IncludeDeleted: p.Args["includeDeleted"] == true,
+	}
+	if s, ok := p.Args["serviceName"].(string); ok {
+		filter.ServiceName = &s
+	}
+	total, err := r.service.GetTotalCost(p.Context, filter)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return total, nil
+}
+
+func (r resolver) createSubscription(p graphql.ResolveParams) (any, error) {
+	input := p.Args["input"].(map[string]any)
+	req := types.PostCreateSubscriptionRequest{
+		ServiceName:   input["serviceName"].(string),
+		Price:         input["price"].(int),
+		UserID:        input["userId"].(string),
+		StartDate:     input["startDate"].(string),
+		EndDate:       optionalString(input["endDate"]),
+		BillingPeriod: optionalString(input["billingPeriod"]),
+	}
+	subs, err := req.ToDomain()
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	if _, err := r.service.CreateSubscription(p.Context, subs); err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return subs, nil
+}
+
+func (r resolver) patchSubscription(p graphql.ResolveParams) (any, error) {
+	subID, err := parseUUID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	input := p.Args["input"].(map[string]any)
+	req := types.PatchSubscriptionByIDRequest{
+		SubscriptionID: subID,
+		ServiceName:    optionalString(input["serviceName"]),
+		EndDate:        optionalString(input["endDate"]),
+		BillingPeriod:  optionalString(input["billingPeriod"]),
+	}
+	if price, ok := input["price"].(int); ok {
+		req.Price = &price
+	}
+	if req.ServiceName == nil && req.Price == nil && req.EndDate == nil && req.BillingPeriod == nil {
+		return nil, toGraphQLError(domain.ErrBadRequest("no fields to update"))
+	}
+	subs, err := req.ToDomain()
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	subs, err = r.service.PatchSubscriptionByID(p.Context, subs)
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return subs, nil
+}
+
+func (r resolver) deleteSubscription(p graphql.ResolveParams) (any, error) {
+	subID, err := parseUUID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	subs, err := r.service.DeleteSubscriptionByID(p.Context, &domain.Subscription{SubscriptionID: subID})
+	if err != nil {
+		return nil, toGraphQLError(err)
+	}
+	return subs, nil
+}
+
+func optionalString(v any) *string {
+	if s, ok := v.(string); ok {
+		return &s
+	}
+	return nil
+}
+
+func parseUUID(v any) (uuid.UUID, error) {
+	s, _ := v.(string)
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, toGraphQLError(domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err)))
+	}
+	return id, nil
+}
+
+func parseMonth(s, field string) (time.Time, error) {
+	t, err := time.Parse(monthLayout, s)
+	if err != nil {
+		return time.Time{}, toGraphQLError(domain.ErrBadRequest(fmt.Sprintf("error while decoding %s: %v", field, err)))
+	}
+	return t, nil
+}
+
+// resolverError carries the code of a domain error to the "extensions" of the GraphQL error.
+type resolverError struct {
+	message string
+	code    int
+}
+
+func (e *resolverError) Error() string {
+	return e.message
+}
+
+func (e *resolverError) Extensions() map[string]any {
+	return map[string]any{"code": e.code}
+}
+
+func toGraphQLError(err error) error {
+	var myErr *domain.MyErr
+	if errors.As(err, &myErr) {
+		return &resolverError{message: myErr.Message, code: myErr.Code}
+	}
+	return &resolverError{message: err.Error(), code: 500}
+}
@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"errors"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps the HTTP-style codes of domain.MyErr to gRPC status codes. Errors that are
+// not domain errors become Internal.
+func toStatus(err error) error {
+	var myErr *domain.MyErr
+	if !errors.As(err, &myErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	code := codes.Unknown
+	switch myErr.Code {
+	case domain.CodeBadRequest:
+		code = codes.InvalidArgument
+	case domain.CodeUnauthorized:
+		code = codes.Unauthenticated
+	case domain.CodeForbidden:
+		code = codes.PermissionDenied
+	case domain.CodeNotFound:
+		code = codes.NotFound
+	case domain.CodeAlreadyExist:
+		code = codes.AlreadyExists
+	case domain.CodePayloadTooLarge:
+		code = codes.ResourceExhausted
+	}
+	return status.Error(code, myErr.Message)
+}
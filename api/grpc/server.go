@@ -0,0 +1,83 @@
+// Package grpc exposes the subscription use cases over gRPC next to the REST API.
+package grpc
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/kasparovgs/subscription-aggregation-service/api/grpc/pb"
+
+	pkgHttp "github.com/kasparovgs/subscription-aggregation-service/pkg/http"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/reqctx"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// NewServer creates a gRPC server with the subscription service registered.
+func NewServer(service usecases.Subcription) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(recoveryInterceptor, actorInterceptor, loggingInterceptor))
+	pb.RegisterSubscriptionServiceServer(server, NewSubscriptionServer(service))
+	return server
+}
+
+// recoveryInterceptor does for gRPC what pkgHttp.RecoveryMiddleware does for HTTP: a panic in
+// a handler is logged with the stack and answered with Internal instead of crashing the server.
+func recoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		rec := recover()
+		if rec == nil {
+			return
+		}
+		slog.ErrorContext(ctx, "panic while serving request",
+			"layer", "grpc_handler",
+			"method", info.FullMethod,
+			"panic", rec,
+			"stack", string(debug.Stack()))
+		err = status.Error(codes.Internal, "the server failed to handle the request")
+	}()
+	return handler(ctx, req)
+}
+
+func loggingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	slog.Log(ctx, level, "request completed",
+		"layer", "grpc_handler",
+		"method", info.FullMethod,
+		"code", status.Code(err),
+		"duration", time.Since(start))
+	return resp, err
+}
+
+// actorInterceptor does for gRPC what pkgHttp.ActorMiddleware and pkgHttp.RequestIDMiddleware
+// do for HTTP, reading the caller identity and request id from the metadata. The request id is
+// sent back in the response header.
+func actorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = reqctx.WithActor(ctx, firstValue(md, pkgHttp.HeaderActor))
+	requestID := pkgHttp.EnsureRequestID(firstValue(md, pkgHttp.HeaderRequestID))
+	ctx = reqctx.WithRequestID(ctx, requestID)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(pkgHttp.HeaderRequestID, requestID))
+	return handler(ctx, req)
+}
+
+func firstValue(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
@@ -0,0 +1,310 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/kasparovgs/subscription-aggregation-service/api/grpc/pb"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeSubscriptions serves the six operations of the gRPC API from memory. err, when set, is
+// returned by every operation; panicking makes every operation panic.
+type fakeSubscriptions struct {
+	usecases.Subcription
+
+	subs      map[uuid.UUID]domain.Subscription
+	err       error
+	panicking bool
+
+	listFilter *domain.SubscriptionFilter
+	costFilter *domain.TotalCostFilter
+}
+
+func (f *fakeSubscriptions) fail() error {
+	if f.panicking {
+		panic("boom")
+	}
+	return f.err
+}
+
+func (f *fakeSubscriptions) CreateSubscription(_ context.Context, subs *domain.Subscription) (uuid.UUID, error) {
+	if err := f.fail(); err != nil {
+		return uuid.Nil, err
+	}
+	subs.SubscriptionID = uuid.New()
+	f.subs[subs.SubscriptionID] = *subs
+	return subs.SubscriptionID, nil
+}
+
+func (f *fakeSubscriptions) GetSubscriptionByID(_ context.Context, subscriptionID uuid.UUID) (*domain.Subscription, error) {
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	subs, ok := f.subs[subscriptionID]
+	if !ok {
+		return nil, domain.ErrNotFound("subscription not found")
+	}
+	return &subs, nil
+}
+
+func (f *fakeSubscriptions) GetListOfSubscriptions(_ context.Context,
+	filter *domain.SubscriptionFilter) ([]domain.Subscription, error) {
+	if err := f.fail(); err != nil {
+		return nil, err
+	}
+	f.listFilter = filter
+	var list []domain.Subscription
+	for _, subs := range f.subs {
+		if filter.UserID == nil || subs.UserID == *filter.UserID {
+			list = append(list, subs)
+		}
+	}
+	return list, nil
+}
+
+func (f *fakeSubscriptions) GetTotalCost(_ context.Context, filter *domain.TotalCostFilter) (int, error) {
+	if err := f.fail(); err != nil {
+		return 0, err
+	}
+	f.costFilter = filter
+	var total int
+	for _, subs := range f.subs {
+		total += subs.Price
+	}
+	return total, nil
+}
+
+func (f *fakeSubscriptions) PatchSubscriptionByID(ctx context.Context, patch *domain.Subscription) (*domain.Subscription, error) {
+	subs, err := f.GetSubscriptionByID(ctx, patch.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if patch.ServiceName != "" {
+		subs.ServiceName = patch.ServiceName
+	}
+	if patch.Price != 0 {
+		subs.Price = patch.Price
+	}
+	if patch.EndDate != nil {
+		subs.EndDate = patch.EndDate
+	}
+	f.subs[subs.SubscriptionID] = *subs
+	return subs, nil
+}
+
+func (f *fakeSubscriptions) DeleteSubscriptionByID(ctx context.Context, del *domain.Subscription) (*domain.Subscription, error) {
+	subs, err := f.GetSubscriptionByID(ctx, del.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	delete(f.subs, subs.SubscriptionID)
+	return subs, nil
+}
+
+// startServer serves NewServer over an in-memory connection and returns a client for it.
+func startServer(t *testing.T, service *fakeSubscriptions) pb.SubscriptionServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	server := NewServer(service)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return pb.NewSubscriptionServiceClient(conn)
+}
+
+func newFakeSubscriptions() *fakeSubscriptions {
+	return &fakeSubscriptions{subs: make(map[uuid.UUID]domain.Subscription)}
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestSubscriptionServerRoundTrip(t *testing.T) {
+	service := newFakeSubscriptions()
+	client := startServer(t, service)
+	ctx := testContext(t)
+	userID := uuid.New()
+	yearly := domain.BillingPeriodYearly
+
+	created, err := client.CreateSubscription(ctx, &pb.CreateSubscriptionRequest{
+		ServiceName:   "Netflix",
+		Price:         799,
+		UserId:        userID.String(),
+		StartDate:     "07-2025",
+		BillingPeriod: &yearly,
+	})
+	if err != nil {
+		t.Fatalf("CreateSubscription: %v", err)
+	}
+
+	got, err := client.GetSubscription(ctx, &pb.GetSubscriptionRequest{SubscriptionId: created.GetSubscriptionId()})
+	if err != nil {
+		t.Fatalf("GetSubscription: %v", err)
+	}
+	if got.GetServiceName() != "Netflix" || got.GetPrice() != 799 || got.GetUserId() != userID.String() ||
+		got.GetStartDate() != "07-2025" || got.EndDate != nil || got.GetBillingPeriod() != yearly {
+		t.Fatalf("GetSubscription returned %v", got)
+	}
+
+	user := userID.String()
+	list, err := client.ListSubscriptions(ctx, &pb.ListSubscriptionsRequest{UserId: &user, IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("ListSubscriptions: %v", err)
+	}
+	if len(list.GetSubscriptions()) != 1 || list.GetSubscriptions()[0].GetSubscriptionId() != created.GetSubscriptionId() {
+		t.Fatalf("ListSubscriptions returned %v", list.GetSubscriptions())
+	}
+	if service.listFilter.UserID == nil || *service.listFilter.UserID != userID || !service.listFilter.IncludeDeleted {
+		t.Fatalf("ListSubscriptions passed filter %+v", service.listFilter)
+	}
+
+	total, err := client.GetTotalCost(ctx, &pb.GetTotalCostRequest{StartDate: "01-2025", EndDate: "12-2025", UserId: &user})
+	if err != nil {
+		t.Fatalf("GetTotalCost: %v", err)
+	}
+	if total.GetTotalCost() != 799 {
+		t.Fatalf("GetTotalCost returned %d; want 799", total.GetTotalCost())
+	}
+	wantStart := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if !service.costFilter.StartDate.Equal(wantStart) || service.costFilter.UserID == nil {
+		t.Fatalf("GetTotalCost passed filter %+v", service.costFilter)
+	}
+
+	price, end := int64(999), "12-2025"
+	patched, err := client.PatchSubscription(ctx, &pb.PatchSubscriptionRequest{
+		SubscriptionId: created.GetSubscriptionId(),
+		Price:          &price,
+		EndDate:        &end,
+	})
+	if err != nil {
+		t.Fatalf("PatchSubscription: %v", err)
+	}
+	if patched.GetPrice() != 999 || patched.GetEndDate() != end || patched.GetServiceName() != "Netflix" {
+		t.Fatalf("PatchSubscription returned %v", patched)
+	}
+
+	deleted, err := client.DeleteSubscription(ctx, &pb.DeleteSubscriptionRequest{SubscriptionId: created.GetSubscriptionId()})
+	if err != nil {
+		t.Fatalf("DeleteSubscription: %v", err)
+	}
+	if deleted.GetSubscriptionId() != created.GetSubscriptionId() {
+		t.Fatalf("DeleteSubscription returned %v", deleted)
+	}
+	_, err = client.GetSubscription(ctx, &pb.GetSubscriptionRequest{SubscriptionId: created.GetSubscriptionId()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("GetSubscription after delete: %v; want NotFound", err)
+	}
+}
+
+func TestSubscriptionServerRejectsInvalidRequests(t *testing.T) {
+	client := startServer(t, newFakeSubscriptions())
+	ctx := testContext(t)
+	badMonth := "2025-07"
+
+	calls := map[string]func() error{
+		"CreateSubscription": func() error {
+			_, err := client.CreateSubscription(ctx, &pb.CreateSubscriptionRequest{UserId: "not-a-uuid", StartDate: "07-2025"})
+			return err
+		},
+		"GetSubscription": func() error {
+			_, err := client.GetSubscription(ctx, &pb.GetSubscriptionRequest{SubscriptionId: "not-a-uuid"})
+			return err
+		},
+		"ListSubscriptions": func() error {
+			_, err := client.ListSubscriptions(ctx, &pb.ListSubscriptionsRequest{StartDate: &badMonth})
+			return err
+		},
+		"GetTotalCost": func() error {
+			_, err := client.GetTotalCost(ctx, &pb.GetTotalCostRequest{StartDate: "01-2025"})
+			return err
+		},
+		"PatchSubscription": func() error {
+			_, err := client.PatchSubscription(ctx, &pb.PatchSubscriptionRequest{SubscriptionId: uuid.NewString()})
+			return err
+		},
+		"DeleteSubscription": func() error {
+			_, err := client.DeleteSubscription(ctx, &pb.DeleteSubscriptionRequest{SubscriptionId: "not-a-uuid"})
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: %v; want InvalidArgument", name, err)
+		}
+	}
+}
+
+func TestSubscriptionServerMapsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{
+		{"bad request", domain.ErrBadRequest("bad"), codes.InvalidArgument},
+		{"unauthorized", domain.ErrUnauthorized("who"), codes.Unauthenticated},
+		{"forbidden", domain.ErrForbidden("no"), codes.PermissionDenied},
+		{"not found", domain.ErrNotFound("gone"), codes.NotFound},
+		{"already exist", domain.ErrAlreadyExist("twice"), codes.AlreadyExists},
+		{"payload too large", domain.ErrPayloadTooLarge("big"), codes.ResourceExhausted},
+		{"unknown domain code", domain.NewError(418, "teapot"), codes.Unknown},
+		{"not a domain error", errors.New("connection refused"), codes.Internal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newFakeSubscriptions()
+			service.err = tt.err
+			client := startServer(t, service)
+
+			_, err := client.GetSubscription(testContext(t), &pb.GetSubscriptionRequest{SubscriptionId: uuid.NewString()})
+			if status.Code(err) != tt.want {
+				t.Fatalf("got %v; want %v", err, tt.want)
+			}
+			var myErr *domain.MyErr
+			if errors.As(tt.err, &myErr) && status.Convert(err).Message() != myErr.Message {
+				t.Fatalf("message %q; want %q", status.Convert(err).Message(), myErr.Message)
+			}
+		})
+	}
+}
+
+func TestSubscriptionServerRecoversFromPanics(t *testing.T) {
+	service := newFakeSubscriptions()
+	service.panicking = true
+	client := startServer(t, service)
+	ctx := testContext(t)
+
+	_, err := client.GetSubscription(ctx, &pb.GetSubscriptionRequest{SubscriptionId: uuid.NewString()})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("got %v; want Internal", err)
+	}
+
+	// The server keeps serving after the panic.
+	service.panicking = false
+	_, err = client.GetSubscription(ctx, &pb.GetSubscriptionRequest{SubscriptionId: uuid.NewString()})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("after the panic: %v; want NotFound", err)
+	}
+}
@@ -0,0 +1,208 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/kasparovgs/subscription-aggregation-service/api/grpc/pb"
+	"github.com/kasparovgs/subscription-aggregation-service/api/http/types"
+
+	"github.com/google/uuid"
+)
+
+const monthLayout = "01-2006"
+
+// SubscriptionServer implements pb.SubscriptionServiceServer on top of the same use cases as
+// the REST handlers. Requests are validated by the REST request types, so both APIs accept
+// the same input.
+type SubscriptionServer struct {
+	pb.UnimplementedSubscriptionServiceServer
+	service usecases.Subcription
+}
+
+func NewSubscriptionServer(service usecases.Subcription) *SubscriptionServer {
+	return &SubscriptionServer{service: service}
+}
+
+func (s *SubscriptionServer) CreateSubscription(ctx context.Context,
+	req *pb.CreateSubscriptionRequest) (*pb.CreateSubscriptionResponse, error) {
+	create := types.PostCreateSubscriptionRequest{
+		ServiceName:   req.GetServiceName(),
+		Price:         int(req.GetPrice()),
+		UserID:        req.GetUserId(),
+		StartDate:     req.GetStartDate(),
+		EndDate:       req.EndDate,
+		BillingPeriod: req.BillingPeriod,
+	}
+	subs, err := create.ToDomain()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	subID, err := s.service.CreateSubscription(ctx, subs)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.CreateSubscriptionResponse{SubscriptionId: subID.String()}, nil
+}
+
+func (s *SubscriptionServer) GetSubscription(ctx context.Context, req *pb.GetSubscriptionRequest) (*pb.Subscription, error) {
+	subID, err := parseUUID(req.GetSubscriptionId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	subs, err := s.service.GetSubscriptionByID(ctx, subID)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(subs), nil
+}
+
+func (s *SubscriptionServer) ListSubscriptions(ctx context.Context,
+	req *pb.ListSubscriptionsRequest) (*pb.ListSubscriptionsResponse, error) {
+	filter := &domain.SubscriptionFilter{ServiceName: req.ServiceName, IncludeDeleted: req.GetIncludeDeleted()}
+	if req.UserId != nil {
+		userID, err := parseUUID(req.GetUserId())
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		filter.UserID = &userID
+	}
+	if req.Price != nil {
+		price := int(req.GetPrice())
+		filter.Price = &price
+	}
+	if req.StartDate != nil {
+		start, err := parseMonth(req.GetStartDate(), "startDate")
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		filter.StartDate = &start
+	}
+	if req.EndDate != nil {
+		end, err := parseMonth(req.GetEndDate(), "endDate")
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		filter.EndDate = &end
+	}
+
+	list, err := s.service.GetListOfSubscriptions(ctx, filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &pb.ListSubscriptionsResponse{Subscriptions: make([]*pb.Subscription, 0, len(list))}
+	for i := range list {
+		resp.Subscriptions = append(resp.Subscriptions, toProto(&list[i]))
+	}
+	return resp, nil
+}
+
+func (s *SubscriptionServer) GetTotalCost(ctx context.Context, req *pb.GetTotalCostRequest) (*pb.GetTotalCostResponse, error) {
+	if req.GetStartDate() == "" || req.GetEndDate() == "" {
+		return nil, toStatus(domain.ErrBadRequest("start_date and end_date are required for the request"))
+	}
+	start, err := parseMonth(req.GetStartDate(), "startDate")
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	end, err := parseMonth(req.GetEndDate(), "endDate")
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	filter := &domain.TotalCostFilter{
+		ServiceName:    req.ServiceName,
+		StartDate:      start,
+		EndDate:        end,
+		IncludeDeleted: req.GetIncludeDeleted(),
+	}
+	if req.UserId != nil {
+		userID, err := parseUUID(req.GetUserId())
+		if err != nil {
+			return nil, toStatus(err)
+		}
+		filter.UserID = &userID
+	}
+
+	total, err := s.service.GetTotalCost(ctx, filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.GetTotalCostResponse{TotalCost: int64(total)}, nil
+}
+
+func (s *SubscriptionServer) PatchSubscription(ctx context.Context, req *pb.PatchSubscriptionRequest) (*pb.Subscription, error) {
+	subID, err := parseUUID(req.GetSubscriptionId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	patch := types.PatchSubscriptionByIDRequest{
+		SubscriptionID: subID,
+		ServiceName:    req.ServiceName,
+		EndDate:        req.EndDate,
+		BillingPeriod:  req.BillingPeriod,
+	}
+	if req.Price != nil {
+		price := int(req.GetPrice())
+		patch.Price = &price
+	}
+	if patch.ServiceName == nil && patch.Price == nil && patch.EndDate == nil && patch.BillingPeriod == nil {
+		return nil, toStatus(domain.ErrBadRequest("no fields to update"))
+	}
+	subs, err := patch.ToDomain()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	subs, err = s.service.PatchSubscriptionByID(ctx, subs)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(subs), nil
+}
+
+func (s *SubscriptionServer) DeleteSubscription(ctx context.Context, req *pb.DeleteSubscriptionRequest) (*pb.Subscription, error) {
+	subID, err := parseUUID(req.GetSubscriptionId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	subs, err := s.service.DeleteSubscriptionByID(ctx, &domain.Subscription{SubscriptionID: subID})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProto(subs), nil
+}
+
+func toProto(subs *domain.Subscription) *pb.Subscription {
+	out := &pb.Subscription{
+		SubscriptionId: subs.SubscriptionID.String(),
+		ServiceName:    subs.ServiceName,
+		Price:          int64(subs.Price),
+		UserId:         subs.UserID.String(),
+		StartDate:      subs.StartDate.Format(monthLayout),
+		BillingPeriod:  subs.BillingPeriod,
+	}
+	if subs.EndDate != nil {
+		end := subs.EndDate.Format(monthLayout)
+		out.EndDate = &end
+	}
+	return out
+}
+
+func parseUUID(s string) (uuid.UUID, error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	return id, nil
+}
+
+func parseMonth(s, field string) (time.Time, error) {
+	t, err := time.Parse(monthLayout, s)
+	if err != nil {
+		return time.Time{}, domain.ErrBadRequest(fmt.Sprintf("error while decoding %s: %v", field, err))
+	}
+	return t, nil
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/kasparovgs/subscription-aggregation-service/api/http/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Audit represents an HTTP handler for reading the subscription change log.
+type Audit struct {
+	service usecases.Audit
+}
+
+// NewAuditHandler creates a new instance of Audit.
+func NewAuditHandler(service usecases.Audit) *Audit {
+	return &Audit{service: service}
+}
+
+// @Summary Get subscription history
+// @ID getSubscriptionHistory
+// @Description Get every recorded change of a subscription, oldest first
+// @Tags audit
+// @Accept  json
+// @Produce json
+// @Param subscription_id path string true "UUID of the subscription" format(uuid)
+// @Success 200 {object} types.GetSubscriptionHistoryResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "No history for subscription"
+// @Router /subscriptions/{subscription_id}/history [get]
+func (a *Audit) getSubscriptionHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	subID, err := types.GetSubscriptionHistoryHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	events, err := a.service.GetSubscriptionHistory(r.Context(), subID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get subscription history", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "subscription history received", "subscription_id", subID)
+	types.ProcessError(w, err, &types.GetSubscriptionHistoryResponse{Events: events})
+}
+
+// @Summary List audit events
+// @ID listEvents
+// @Description Get subscription change events with the ability to filter
+// @Tags audit
+// @Accept  json
+// @Produce json
+// @Param subscription_id query string false "UUID of the subscription"
+// @Param actor query string false "Actor who made the change"
+// @Param action query string false "Action (created, updated, replaced, deleted, restored, price_change_scheduled, price_change_deleted)"
+// @Param from query string false "Occurred at or after (RFC3339)"
+// @Param to query string false "Occurred at or before (RFC3339)"
+// @Param limit query int false "Max number of events (default and max 1000)"
+// @Param offset query int false "Number of events to skip"
+// @Success 200 {object} types.GetListOfEventsResponse
+// @Failure 400 {string} string "Bad request"
+// @Router /audit [get]
+func (a *Audit) getListOfEventsHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := types.GetListOfEventsHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	events, err := a.service.GetListOfEvents(r.Context(), filter)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get audit events by filter", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "audit events by filter successfully found")
+	types.ProcessError(w, err, &types.GetListOfEventsResponse{Events: events})
+}
+
+func (a *Audit) WithAuditHandlers(r chi.Router) {
+	r.Get("/subscriptions/{subscription_id}/history", a.getSubscriptionHistoryHandler)
+	r.Get("/audit", a.getListOfEventsHandler)
+}
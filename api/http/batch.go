@@ -0,0 +1,71 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/kasparovgs/subscription-aggregation-service/api/http/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Batch represents an HTTP handler for applying many subscription changes at once.
+type Batch struct {
+	service usecases.Batch
+}
+
+// NewBatchHandler creates a new instance of Batch.
+func NewBatchHandler(service usecases.Batch) *Batch {
+	return &Batch{service: service}
+}
+
+// @Summary Apply a batch of changes
+// @ID applyBatch
+// @Description Apply a list of create, patch and delete operations in one transaction. In atomic mode (default) the first failed operation rolls back the whole batch; in best_effort mode failed operations are rolled back on their own and the rest is committed. Results are reported per operation index.
+// @Tags subscription
+// @Accept  json
+// @Produce json
+// @Param request body types.PostApplyBatchRequest true "Mode and operations"
+// @Success 200 {object} types.PostApplyBatchResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 422 {object} types.PostApplyBatchResponse "Atomic batch rolled back"
+// @Failure 500 {string} string "Internal server error"
+// @Router /subscriptions/batch [post]
+func (b *Batch) postApplyBatchHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := types.PostApplyBatchHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	ops, err := req.ToDomain()
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to convert request to domain", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+
+	result, err := b.service.ApplyBatch(r.Context(), ops, req.Mode)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to apply batch", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	if !result.Committed {
+		slog.WarnContext(r.Context(), "batch rolled back", "operations", len(ops))
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	} else {
+		slog.InfoContext(r.Context(), "batch applied", "operations", len(ops), "mode", result.Mode)
+	}
+	types.ProcessError(w, nil, &types.PostApplyBatchResponse{
+		Mode:      result.Mode,
+		Committed: result.Committed,
+		Results:   result.Results,
+	})
+}
+
+func (b *Batch) WithBatchHandlers(r chi.Router) {
+	r.Post("/subscriptions/batch", b.postApplyBatchHandler)
+}
@@ -0,0 +1,202 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/kasparovgs/subscription-aggregation-service/api/http/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Budget represents an HTTP handler for managing spend limits.
+type Budget struct {
+	service usecases.Budget
+}
+
+// NewBudgetHandler creates a new instance of Budget.
+func NewBudgetHandler(service usecases.Budget) *Budget {
+	return &Budget{service: service}
+}
+
+// @Summary Create a budget
+// @ID createBudget
+// @Description Set a monthly spend limit for a user, optionally for a single service
+// @Tags budget
+// @Accept  json
+// @Produce json
+// @Param request body types.PostCreateBudgetRequest true "Budget"
+// @Success 201 {object} types.PostCreateBudgetResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 409 {string} string "Budget already exists"
+// @Router /budgets [post]
+func (b *Budget) postCreateBudgetHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := types.CreatePostBudgetHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	budget, err := req.ToDomain()
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to convert request to domain", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	budget, err = b.service.CreateBudget(r.Context(), budget)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to create budget in service", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "budget created", "budget_id", budget.BudgetID)
+	types.ProcessError(w, err, &types.PostCreateBudgetResponse{Budget: *budget})
+}
+
+// @Summary Get a budget
+// @ID getBudget
+// @Description Get a budget by their budgetID
+// @Tags budget
+// @Accept  json
+// @Produce json
+// @Param budget_id path string true "UUID of the budget" format(uuid)
+// @Success 200 {object} types.GetBudgetByIDResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Budget not found"
+// @Router /budgets/{budget_id} [get]
+func (b *Budget) getBudgetByIDHandler(w http.ResponseWriter, r *http.Request) {
+	budgetID, err := types.GetBudgetByIDHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	budget, err := b.service.GetBudgetByID(r.Context(), budgetID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get budget by budgetID", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	types.ProcessError(w, err, &types.GetBudgetByIDResponse{Budget: *budget})
+}
+
+// @Summary List budgets
+// @ID listBudgets
+// @Description Get budgets, optionally of a single user
+// @Tags budget
+// @Accept  json
+// @Produce json
+// @Param user_id query string false "userUUID"
+// @Success 200 {object} types.GetListOfBudgetsResponse
+// @Failure 400 {string} string "Bad request"
+// @Router /budgets [get]
+func (b *Budget) getListOfBudgetsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := types.GetListOfBudgetsHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	budgets, err := b.service.GetListOfBudgets(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get list of budgets", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	types.ProcessError(w, err, &types.GetListOfBudgetsResponse{Budgets: budgets})
+}
+
+// @Summary Patch a budget
+// @ID patchBudget
+// @Description Change the monthly limit of a budget
+// @Tags budget
+// @Accept  json
+// @Produce json
+// @Param budget_id path string true "UUID of the budget" format(uuid)
+// @Param request body types.PatchBudgetByIDRequest true "Fields to update"
+// @Success 200 {object} types.PatchBudgetByIDResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Budget not found"
+// @Router /budgets/{budget_id} [patch]
+func (b *Budget) patchBudgetByIDHandler(w http.ResponseWriter, r *http.Request) {
+	budget, err := types.PatchBudgetByIDHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	budget, err = b.service.PatchBudgetByID(r.Context(), budget)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to patch budget by budgetID", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "budget patched", "budget_id", budget.BudgetID)
+	types.ProcessError(w, err, &types.PatchBudgetByIDResponse{Budget: *budget})
+}
+
+// @Summary Delete a budget
+// @ID deleteBudget
+// @Description Delete a budget and its alerts
+// @Tags budget
+// @Accept  json
+// @Produce json
+// @Param budget_id path string true "UUID of the budget" format(uuid)
+// @Success 204
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Budget not found"
+// @Router /budgets/{budget_id} [delete]
+func (b *Budget) deleteBudgetByIDHandler(w http.ResponseWriter, r *http.Request) {
+	budgetID, err := types.GetBudgetByIDHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	if err := b.service.DeleteBudgetByID(r.Context(), budgetID); err != nil {
+		slog.ErrorContext(r.Context(), "failed to delete budget by budgetID", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "budget deleted", "budget_id", budgetID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get budget status of a user
+// @ID getBudgetStatus
+// @Description Get used and remaining amount of every budget of a user for a month
+// @Tags budget
+// @Accept  json
+// @Produce json
+// @Param user_id path string true "UUID of the user" format(uuid)
+// @Param month query string false "Month (MM-YYYY), current month by default"
+// @Success 200 {object} types.GetBudgetStatusResponse
+// @Failure 400 {string} string "Bad request"
+// @Router /users/{user_id}/budget-status [get]
+func (b *Budget) getBudgetStatusHandler(w http.ResponseWriter, r *http.Request) {
+	userID, month, err := types.GetBudgetStatusHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	statuses, err := b.service.GetBudgetStatus(r.Context(), userID, month)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get budget status", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "budget status received", "user_id", userID)
+	types.ProcessError(w, err, &types.GetBudgetStatusResponse{Budgets: statuses})
+}
+
+func (b *Budget) WithBudgetHandlers(r chi.Router) {
+	r.Post("/budgets", b.postCreateBudgetHandler)
+	r.Get("/budgets", b.getListOfBudgetsHandler)
+	r.Get("/budgets/{budget_id}", b.getBudgetByIDHandler)
+	r.Patch("/budgets/{budget_id}", b.patchBudgetByIDHandler)
+	r.Delete("/budgets/{budget_id}", b.deleteBudgetByIDHandler)
+	r.Get("/users/{user_id}/budget-status", b.getBudgetStatusHandler)
+}
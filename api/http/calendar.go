@@ -0,0 +1,123 @@
+package http
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/kasparovgs/subscription-aggregation-service/api/http/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Calendar represents an HTTP handler for the iCalendar feed of renewals and expirations.
+type Calendar struct {
+	service usecases.Calendar
+}
+
+// NewCalendarHandler creates a new instance of Calendar.
+func NewCalendarHandler(service usecases.Calendar) *Calendar {
+	return &Calendar{service: service}
+}
+
+// @Summary Create a calendar token
+// @ID createCalendarToken
+// @Description Issue the secret token of the calendar feed of a user. The previous token stops working. The token is shown only once.
+// @Tags calendar
+// @Accept  json
+// @Produce json
+// @Param user_id path string true "UUID of the user" format(uuid)
+// @Success 201 {object} types.PostCreateCalendarTokenResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /users/{user_id}/calendar-token [post]
+func (c *Calendar) postCreateCalendarTokenHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := types.PostCreateCalendarTokenHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	token, err := c.service.CreateCalendarToken(r.Context(), userID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to create calendar token", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "calendar token created", "user_id", userID)
+	types.ProcessError(w, err, &types.PostCreateCalendarTokenResponse{
+		Token: token,
+		URL:   fmt.Sprintf("/users/%s/calendar.ics?token=%s", userID, url.QueryEscape(token)),
+	})
+}
+
+// @Summary Revoke the calendar token
+// @ID deleteCalendarToken
+// @Description Disable the calendar feed of a user
+// @Tags calendar
+// @Accept  json
+// @Produce json
+// @Param user_id path string true "UUID of the user" format(uuid)
+// @Success 204
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Calendar token not found"
+// @Router /users/{user_id}/calendar-token [delete]
+func (c *Calendar) deleteCalendarTokenHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := types.PostCreateCalendarTokenHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	if err := c.service.RevokeCalendarToken(r.Context(), userID); err != nil {
+		slog.ErrorContext(r.Context(), "failed to revoke calendar token", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "calendar token revoked", "user_id", userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Calendar feed
+// @ID getCalendar
+// @Description RFC 5545 feed with an all-day event for every upcoming billing date and subscription end of a user
+// @Tags calendar
+// @Produce text/calendar
+// @Param user_id path string true "UUID of the user" format(uuid)
+// @Param token query string true "Calendar token"
+// @Param months query int false "How many months ahead (1-36, default 12)"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 400 {string} string "Bad request"
+// @Failure 401 {string} string "Invalid calendar token"
+// @Router /users/{user_id}/calendar.ics [get]
+func (c *Calendar) getCalendarHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := types.GetCalendarHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	events, err := c.service.GetCalendarEvents(r.Context(), req.UserID, req.Token, req.Months)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get calendar events", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="subscriptions.ics"`)
+	if err := types.CalendarFromDomain(events).Write(w); err != nil {
+		slog.ErrorContext(r.Context(), "failed to write calendar", "error", err)
+		return
+	}
+	slog.InfoContext(r.Context(), "calendar feed served", "user_id", req.UserID, "events", len(events))
+}
+
+func (c *Calendar) WithCalendarHandlers(r chi.Router) {
+	r.Post("/users/{user_id}/calendar-token", c.postCreateCalendarTokenHandler)
+	r.Delete("/users/{user_id}/calendar-token", c.deleteCalendarTokenHandler)
+	r.Get("/users/{user_id}/calendar.ics", c.getCalendarHandler)
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/kasparovgs/subscription-aggregation-service/api/http/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Health represents an HTTP handler for the liveness and readiness probes.
+type Health struct {
+	service usecases.Health
+}
+
+// NewHealthHandler creates a new instance of Health.
+func NewHealthHandler(service usecases.Health) *Health {
+	return &Health{service: service}
+}
+
+// @Summary Liveness probe
+// @ID getLiveness
+// @Description Reports that the process is running. It does not check any dependency.
+// @Tags health
+// @Produce json
+// @Success 200 {object} types.GetLivenessResponse
+// @Router /healthz [get]
+func (h *Health) getLivenessHandler(w http.ResponseWriter, r *http.Request) {
+	types.ProcessError(w, nil, &types.GetLivenessResponse{Status: domain.HealthStatusOK})
+}
+
+// @Summary Readiness probe
+// @ID getReadiness
+// @Description Checks the database connection and the applied migration version. Fails while the service is shutting down.
+// @Tags health
+// @Produce json
+// @Success 200 {object} types.GetReadinessResponse
+// @Failure 503 {object} types.GetReadinessResponse
+// @Router /readyz [get]
+func (h *Health) getReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	report := h.service.Readiness(r.Context())
+	if report.Status != domain.HealthStatusOK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	types.ProcessError(w, nil, types.ReadinessFromDomain(report))
+}
+
+func (h *Health) WithHealthHandlers(r chi.Router) {
+	r.Get("/healthz", h.getLivenessHandler)
+	r.Get("/readyz", h.getReadinessHandler)
+}
@@ -0,0 +1,154 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/kasparovgs/subscription-aggregation-service/api/http/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Statement represents an HTTP handler for finding subscriptions in bank statements.
+type Statement struct {
+	service usecases.Statement
+}
+
+// NewStatementHandler creates a new instance of Statement.
+func NewStatementHandler(service usecases.Statement) *Statement {
+	return &Statement{service: service}
+}
+
+// @Summary Import a bank statement
+// @ID importStatement
+// @Description Upload a bank statement (CSV, OFX or QFX) of a user. Charges to the same merchant with a similar amount repeating monthly or yearly are returned as subscription candidates to confirm. CSV files need a header line with date, description and amount columns.
+// @Tags statement
+// @Accept text/csv
+// @Accept application/x-ofx
+// @Produce json
+// @Param user_id path string true "UUID of the user" format(uuid)
+// @Param format query string false "Statement format, taken from Content-Type when omitted" Enums(csv, ofx, qfx)
+// @Param statement body string true "Statement file"
+// @Success 200 {object} types.PostImportStatementResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /users/{user_id}/statements [post]
+func (s *Statement) postImportStatementHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := types.PostImportStatementHandlerRequest(w, r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	defer r.Body.Close()
+
+	candidates, err := s.service.ImportStatement(r.Context(), req.UserID, req.Format, req.Body)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to import bank statement", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "bank statement imported", "user_id", req.UserID, "candidates", len(candidates))
+	types.ProcessError(w, err, &types.PostImportStatementResponse{Candidates: candidates})
+}
+
+// @Summary List subscription candidates
+// @ID listSubscriptionCandidates
+// @Description Get the subscription candidates found in the bank statements of a user
+// @Tags statement
+// @Accept  json
+// @Produce json
+// @Param user_id path string true "UUID of the user" format(uuid)
+// @Param status query string false "Candidate status" Enums(pending, confirmed, dismissed)
+// @Success 200 {object} types.GetListOfSubscriptionCandidatesResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /users/{user_id}/subscription-candidates [get]
+func (s *Statement) getListOfSubscriptionCandidatesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, status, err := types.GetListOfSubscriptionCandidatesHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	candidates, err := s.service.GetListOfSubscriptionCandidates(r.Context(), userID, status)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get subscription candidates", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	types.ProcessError(w, err, &types.GetListOfSubscriptionCandidatesResponse{Candidates: candidates})
+}
+
+// @Summary Confirm a subscription candidate
+// @ID confirmSubscriptionCandidate
+// @Description Create a subscription from a detected candidate. Fields of the body, all optional, replace the detected values.
+// @Tags statement
+// @Accept  json
+// @Produce json
+// @Param candidate_id path string true "UUID of the candidate" format(uuid)
+// @Param request body types.PostConfirmSubscriptionCandidateRequest false "Corrections"
+// @Success 201 {object} types.PostConfirmSubscriptionCandidateResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Candidate not found"
+// @Failure 409 {string} string "Candidate is already confirmed or dismissed"
+// @Router /subscription-candidates/{candidate_id}/confirm [post]
+func (s *Statement) postConfirmSubscriptionCandidateHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := types.PostConfirmSubscriptionCandidateHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	overrides, err := req.ToDomain()
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to convert request to domain", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	subID, err := s.service.ConfirmSubscriptionCandidate(r.Context(), req.CandidateID, overrides)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to confirm subscription candidate", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "subscription candidate confirmed", "candidate_id", req.CandidateID, "subscription_id", subID)
+	types.ProcessError(w, err, &types.PostConfirmSubscriptionCandidateResponse{SubscriptionID: subID})
+}
+
+// @Summary Dismiss a subscription candidate
+// @ID dismissSubscriptionCandidate
+// @Description Mark a detected candidate as not a subscription
+// @Tags statement
+// @Accept  json
+// @Produce json
+// @Param candidate_id path string true "UUID of the candidate" format(uuid)
+// @Success 204
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Candidate not found"
+// @Failure 409 {string} string "Candidate is already confirmed or dismissed"
+// @Router /subscription-candidates/{candidate_id}/dismiss [post]
+func (s *Statement) postDismissSubscriptionCandidateHandler(w http.ResponseWriter, r *http.Request) {
+	candidateID, err := types.PostDismissSubscriptionCandidateHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	if err := s.service.DismissSubscriptionCandidate(r.Context(), candidateID); err != nil {
+		slog.ErrorContext(r.Context(), "failed to dismiss subscription candidate", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "subscription candidate dismissed", "candidate_id", candidateID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Statement) WithStatementHandlers(r chi.Router) {
+	r.Post("/users/{user_id}/statements", s.postImportStatementHandler)
+	r.Get("/users/{user_id}/subscription-candidates", s.getListOfSubscriptionCandidatesHandler)
+	r.Post("/subscription-candidates/{candidate_id}/confirm", s.postConfirmSubscriptionCandidateHandler)
+	r.Post("/subscription-candidates/{candidate_id}/dismiss", s.postDismissSubscriptionCandidateHandler)
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/kasparovgs/subscription-aggregation-service/usecases"
 
@@ -22,6 +23,7 @@ func NewSubscriptionHandler(service usecases.Subcription) *Subscription {
 }
 
 // @Summary Create a new subscription
+// @ID createSubscription
 // @Description Create a new subscription and issue their subscriptionID
 // @Tags subscription
 // @Accept  json
@@ -33,29 +35,77 @@ func NewSubscriptionHandler(service usecases.Subcription) *Subscription {
 func (s *Subscription) postCreateSubscriptionHandler(w http.ResponseWriter, r *http.Request) {
 	req, err := types.CreatePostSubscriptionHandlerRequest(r)
 	if err != nil {
-		slog.Warn("failed to parse request", "error", err)
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
 	subscription, err := req.ToDomain()
 	if err != nil {
-		slog.Warn("failed to convert request to domain", "error", err)
+		slog.WarnContext(r.Context(), "failed to convert request to domain", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
 
-	subID, err := s.service.CreateSubscription(subscription)
+	subID, err := s.service.CreateSubscription(r.Context(), subscription)
 	if err != nil {
-		slog.Error("failed to create subscription in service", "error", err)
+		slog.ErrorContext(r.Context(), "failed to create subscription in service", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
 
-	slog.Info("subscription created", "subscription_id", subID)
+	slog.InfoContext(r.Context(), "subscription created", "subscription_id", subID)
 	types.ProcessError(w, err, &types.PostCreateSubscriptionResponse{SubscriptionID: subID})
 }
 
+// @Summary Import subscriptions
+// @ID importSubscriptions
+// @Description Create many subscriptions at once from a CSV file (Content-Type text/csv, header line with service_name, price, user_id, start_date and optional end_date, billing_period columns) or a JSON array. Every row is validated like a single create; if any row is rejected nothing is imported and the response lists the errors by row. All rows are inserted in a single transaction. With dry_run=true the rows are only validated.
+// @Tags subscription
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param dry_run query bool false "Only validate the rows"
+// @Param request body []types.PostCreateSubscriptionRequest true "Subscriptions to import"
+// @Success 200 {object} types.PostImportSubscriptionsResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 422 {object} types.PostImportSubscriptionsResponse "Some rows are invalid"
+// @Failure 500 {string} string "Internal server error"
+// @Router /subscriptions/import [post]
+func (s *Subscription) postImportSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := types.PostImportSubscriptionsHandlerRequest(w, r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	subscriptions, rowErrors := req.ToDomain()
+	resp := &types.PostImportSubscriptionsResponse{
+		DryRun: req.DryRun,
+		Total:  len(subscriptions) + len(rowErrors),
+		Errors: rowErrors,
+	}
+	if len(rowErrors) > 0 {
+		slog.WarnContext(r.Context(), "import rejected", "invalid_rows", len(rowErrors))
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		types.ProcessError(w, nil, resp)
+		return
+	}
+
+	ids, err := s.service.ImportSubscriptions(r.Context(), subscriptions, req.DryRun)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to import subscriptions in service", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	resp.Imported = len(ids)
+	resp.SubscriptionIDs = ids
+
+	slog.InfoContext(r.Context(), "subscriptions imported", "imported", resp.Imported, "dry_run", req.DryRun)
+	types.ProcessError(w, err, resp)
+}
+
 // @Summary Get a subscription
+// @ID getSubscription
 // @Description Get a subscription by their subscriptionID
 // @Tags subscription
 // @Accept  json
@@ -68,27 +118,29 @@ func (s *Subscription) postCreateSubscriptionHandler(w http.ResponseWriter, r *h
 func (s *Subscription) getSubscriptionByIDHandler(w http.ResponseWriter, r *http.Request) {
 	subs, err := types.GetSubscriptionByIDHandlerRequest(r)
 	if err != nil {
-		slog.Warn("failed to parse request", "error", err)
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
-	subs, err = s.service.GetSubscriptionByID(subs.SubscriptionID)
+	subs, err = s.service.GetSubscriptionByID(r.Context(), subs.SubscriptionID)
 	if err != nil {
-		slog.Error("failed to get subscription by subscriptionID", "error", err)
+		slog.ErrorContext(r.Context(), "failed to get subscription by subscriptionID", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
-	slog.Info("subscription received", "subscription_id", subs.SubscriptionID)
+	slog.InfoContext(r.Context(), "subscription received", "subscription_id", subs.SubscriptionID)
 	types.ProcessError(w, err, &types.GetSubscriptionByIDResponse{SubscriptionID: subs.SubscriptionID,
-		ServiceName: subs.ServiceName,
-		Price:       subs.Price,
-		UserID:      subs.UserID,
-		StartDate:   subs.StartDate,
-		EndDate:     subs.EndDate,
+		ServiceName:   subs.ServiceName,
+		Price:         subs.Price,
+		UserID:        subs.UserID,
+		StartDate:     subs.StartDate,
+		EndDate:       subs.EndDate,
+		BillingPeriod: subs.BillingPeriod,
 	})
 }
 
 // @Summary Patch a subscription
+// @ID patchSubscription
 // @Description Patch a subscription by their subscriptionID
 // @Tags subscription
 // @Accept  json
@@ -102,30 +154,68 @@ func (s *Subscription) getSubscriptionByIDHandler(w http.ResponseWriter, r *http
 func (s *Subscription) patchSubscriptionByIDHandler(w http.ResponseWriter, r *http.Request) {
 	req, err := types.PatchSubscriptionByIDHandlerRequest(r)
 	if err != nil {
-		slog.Warn("failed to parse request", "error", err)
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
 	subscription, err := req.ToDomain()
 	if err != nil {
-		slog.Warn("failed to convert request to domain", "error", err)
+		slog.WarnContext(r.Context(), "failed to convert request to domain", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
-	subs, err := s.service.PatchSubscriptionByID(subscription)
+	subs, err := s.service.PatchSubscriptionByID(r.Context(), subscription)
 	if err != nil {
-		slog.Error("failed to patch subscription by subscriptionID", "error", err)
+		slog.ErrorContext(r.Context(), "failed to patch subscription by subscriptionID", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
-	slog.Info("subscription patched", "subscription_id", subscription.SubscriptionID)
+	slog.InfoContext(r.Context(), "subscription patched", "subscription_id", subscription.SubscriptionID)
 	types.ProcessError(w, err, &types.PatchSubscriptionByIDResponse{SubscriptionID: subs.SubscriptionID,
 		ServiceName: subs.ServiceName, Price: subs.Price, UserID: subs.UserID, StartDate: subs.StartDate,
-		EndDate: subs.EndDate})
+		EndDate: subs.EndDate, BillingPeriod: subs.BillingPeriod})
+}
+
+// @Summary Replace a subscription
+// @ID replaceSubscription
+// @Description Replace all fields of a subscription by their subscriptionID
+// @Tags subscription
+// @Accept  json
+// @Produce json
+// @Param subscription_id path string true "UUID of the subscription" format(uuid)
+// @Param request body types.PutReplaceSubscriptionByIDRequest true "Full subscription"
+// @Success 200 {object} types.PutReplaceSubscriptionByIDResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Subscription not found"
+// @Router /subscriptions/{subscription_id} [put]
+func (s *Subscription) putReplaceSubscriptionByIDHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := types.PutReplaceSubscriptionByIDHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	subscription, err := req.ToDomain()
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to convert request to domain", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	subs, err := s.service.ReplaceSubscription(r.Context(), subscription)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to replace subscription by subscriptionID", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "subscription replaced", "subscription_id", subs.SubscriptionID)
+	types.ProcessError(w, err, &types.PutReplaceSubscriptionByIDResponse{SubscriptionID: subs.SubscriptionID,
+		ServiceName: subs.ServiceName, Price: subs.Price, UserID: subs.UserID, StartDate: subs.StartDate,
+		EndDate: subs.EndDate, BillingPeriod: subs.BillingPeriod})
 }
 
 // @Summary Delete a subscription
-// @Description Delete a subscription by their subscriptionID
+// @ID deleteSubscription
+// @Description Soft-delete a subscription by their subscriptionID. It can be restored until the retention purge removes it
 // @Tags subscription
 // @Accept  json
 // @Produce json
@@ -137,23 +227,54 @@ func (s *Subscription) patchSubscriptionByIDHandler(w http.ResponseWriter, r *ht
 func (s *Subscription) deleteSubscriptionByIDHandler(w http.ResponseWriter, r *http.Request) {
 	subs, err := types.GetSubscriptionByIDHandlerRequest(r)
 	if err != nil {
-		slog.Warn("failed to parse request", "error", err)
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
-	subs, err = s.service.DeleteSubscriptionByID(subs)
+	subs, err = s.service.DeleteSubscriptionByID(r.Context(), subs)
 	if err != nil {
-		slog.Error("failed to delete subscription by subscriptionID", "error", err)
+		slog.ErrorContext(r.Context(), "failed to delete subscription by subscriptionID", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
-	slog.Info("subscription deleted", "subscription_id", subs.SubscriptionID)
+	slog.InfoContext(r.Context(), "subscription deleted", "subscription_id", subs.SubscriptionID)
 	types.ProcessError(w, err, &types.DeleteSubscriptionByIDResponse{SubscriptionID: subs.SubscriptionID,
 		ServiceName: subs.ServiceName, Price: subs.Price, UserID: subs.UserID, StartDate: subs.StartDate,
-		EndDate: subs.EndDate})
+		EndDate: subs.EndDate, BillingPeriod: subs.BillingPeriod})
+}
+
+// @Summary Restore a subscription
+// @ID restoreSubscription
+// @Description Restore a soft-deleted subscription by their subscriptionID
+// @Tags subscription
+// @Accept  json
+// @Produce json
+// @Param subscription_id path string true "UUID of the subscription" format(uuid)
+// @Success 200 {object} types.RestoreSubscriptionByIDResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Deleted subscription not found"
+// @Router /subscriptions/{subscription_id}/restore [post]
+func (s *Subscription) restoreSubscriptionByIDHandler(w http.ResponseWriter, r *http.Request) {
+	subs, err := types.RestoreSubscriptionByIDHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	subs, err = s.service.RestoreSubscriptionByID(r.Context(), subs)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to restore subscription by subscriptionID", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "subscription restored", "subscription_id", subs.SubscriptionID)
+	types.ProcessError(w, err, &types.RestoreSubscriptionByIDResponse{SubscriptionID: subs.SubscriptionID,
+		ServiceName: subs.ServiceName, Price: subs.Price, UserID: subs.UserID, StartDate: subs.StartDate,
+		EndDate: subs.EndDate, BillingPeriod: subs.BillingPeriod})
 }
 
 // @Summary List subscriptions
+// @ID listSubscriptions
 // @Description Get a list of subscriptions with the ability to filter
 // @Tags subscription
 // @Accept  json
@@ -162,27 +283,74 @@ func (s *Subscription) deleteSubscriptionByIDHandler(w http.ResponseWriter, r *h
 // @Param service_name query string false "Service name"
 // @Param start_date query string false "Start date (MM-YYYY)"
 // @Param end_date query string false "End date (MM-YYYY)"
-// @Success 200 {array} types.GetSubscriptionByIDResponse
+// @Param include_deleted query bool false "Include soft-deleted subscriptions"
+// @Success 200 {object} types.GetListOfSubscriptionsResponse
 // @Failure 400 {string} string "Bad request"
 // @Router /subscriptions [get]
 func (s *Subscription) getListOfSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
 	req, err := types.GetListOfSubscriptionsHandlerRequest(r)
 	if err != nil {
-		slog.Warn("failed to parse request", "error", err)
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
-	list, err := s.service.GetListOfSubscriptions(req)
+	list, err := s.service.GetListOfSubscriptions(r.Context(), req)
 	if err != nil {
-		slog.Error("filed to get list of subscriptions by filter", "error", err)
+		slog.ErrorContext(r.Context(), "filed to get list of subscriptions by filter", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
-	slog.Info("list of subscriptions by filter successfully found")
+	slog.InfoContext(r.Context(), "list of subscriptions by filter successfully found")
 	types.ProcessError(w, err, &types.GetListOfSubscriptionsResponse{Subscriptions: list})
 }
 
+// @Summary Export subscriptions
+// @ID exportSubscriptions
+// @Description Download the subscriptions matching the filter as CSV or JSON Lines. Rows are streamed as they are read from the database.
+// @Tags subscription
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string false "Export format" Enums(csv, jsonl) default(csv)
+// @Param user_id query string false "User ID (UUID)"
+// @Param service_name query string false "Service name"
+// @Param price query int false "Price"
+// @Param start_date query string false "Start date (MM-YYYY)"
+// @Param end_date query string false "End date (MM-YYYY)"
+// @Param include_deleted query bool false "Include soft-deleted subscriptions"
+// @Success 200 {file} file "Subscriptions"
+// @Failure 400 {string} string "Bad request"
+// @Failure 500 {string} string "Internal server error"
+// @Router /subscriptions/export [get]
+func (s *Subscription) getExportSubscriptionsHandler(w http.ResponseWriter, r *http.Request) {
+	filter, format, err := types.GetExportSubscriptionsHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	// An export streams for as long as the table takes to read, so the server write
+	// timeout must not cut it off.
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		slog.WarnContext(r.Context(), "failed to clear write deadline", "error", err)
+	}
+	exporter := types.NewSubscriptionExporter(w, format)
+	err = s.service.ExportSubscriptions(r.Context(), filter, exporter.Write)
+	if err == nil {
+		err = exporter.Close()
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to export subscriptions", "error", err)
+		// once rows have been sent the status can't be changed, the client gets a truncated file
+		if !exporter.Started() {
+			types.ProcessError(w, err, nil)
+		}
+		return
+	}
+	slog.InfoContext(r.Context(), "subscriptions successfully exported", "format", format)
+}
+
 // @Summary Get total cost of subscriptions
+// @ID getTotalCost
 // @Description Returns the total cost of all subscriptions that are active within the given period with optional filtering by user_id and service_name.
 // @Tags subscription
 // @Accept  json
@@ -191,6 +359,7 @@ func (s *Subscription) getListOfSubscriptionsHandler(w http.ResponseWriter, r *h
 // @Param end_date query string true "End date (format: MM-YYY)"
 // @Param user_id query string false "User ID (UUID)"
 // @Param service_name query string false "Service name"
+// @Param include_deleted query bool false "Include soft-deleted subscriptions"
 // @Success 200 {object} types.GetTotalCostResponse
 // @Failure 400 {string} string "Bad request"
 // @Failure 500 {string} string "Internal server error"
@@ -198,25 +367,153 @@ func (s *Subscription) getListOfSubscriptionsHandler(w http.ResponseWriter, r *h
 func (s *Subscription) getTotalCostHandler(w http.ResponseWriter, r *http.Request) {
 	costFilter, err := types.GetTotalCostHandlerRequest(r)
 	if err != nil {
-		slog.Warn("failed to parse request", "error", err)
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
-	cost, err := s.service.GetTotalCost(costFilter)
+	cost, err := s.service.GetTotalCost(r.Context(), costFilter)
 	if err != nil {
-		slog.Error("filed to get total cost of subscriptions by filter", "error", err)
+		slog.ErrorContext(r.Context(), "filed to get total cost of subscriptions by filter", "error", err)
 		types.ProcessError(w, err, nil)
 		return
 	}
-	slog.Info("total cost of subscriptions by filter successfully received")
+	slog.InfoContext(r.Context(), "total cost of subscriptions by filter successfully received")
 	types.ProcessError(w, err, &types.GetTotalCostResponse{TotalCost: cost})
 }
 
+// @Summary Forecast spend
+// @ID getForecast
+// @Description Project month-by-month spend of active subscriptions, honoring end dates, billing periods and scheduled price changes. Subscriptions listed in exclude are left out to show the effect of cancelling them.
+// @Tags subscription
+// @Accept  json
+// @Produce json
+// @Param months query int false "Number of months (1-120, default 12)"
+// @Param from query string false "First month (MM-YYYY), current month by default"
+// @Param user_id query string false "User ID (UUID)"
+// @Param service_name query string false "Service name"
+// @Param exclude query []string false "Subscription IDs to leave out" collectionFormat(csv)
+// @Success 200 {object} types.GetForecastResponse
+// @Failure 400 {string} string "Bad request"
+// @Router /subscriptions/forecast [get]
+func (s *Subscription) getForecastHandler(w http.ResponseWriter, r *http.Request) {
+	filter, err := types.GetForecastHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	forecast, err := s.service.GetForecast(r.Context(), filter)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get forecast", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	var total int
+	for _, month := range forecast {
+		total += month.Total
+	}
+	slog.InfoContext(r.Context(), "forecast successfully calculated")
+	types.ProcessError(w, err, &types.GetForecastResponse{Total: total, Months: forecast})
+}
+
+// @Summary Schedule a price change
+// @ID schedulePriceChange
+// @Description Schedule a new price of a subscription starting with the given month
+// @Tags subscription
+// @Accept  json
+// @Produce json
+// @Param subscription_id path string true "UUID of the subscription" format(uuid)
+// @Param request body types.PostSchedulePriceChangeRequest true "Month (MM-YYYY) and new price"
+// @Success 201 {object} types.PostSchedulePriceChangeResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Subscription not found"
+// @Failure 409 {string} string "Price change for this month already exists"
+// @Router /subscriptions/{subscription_id}/price-changes [post]
+func (s *Subscription) postSchedulePriceChangeHandler(w http.ResponseWriter, r *http.Request) {
+	change, err := types.PostSchedulePriceChangeHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	change, err = s.service.SchedulePriceChange(r.Context(), change)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to schedule price change", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "price change scheduled", "price_change_id", change.PriceChangeID)
+	types.ProcessError(w, err, &types.PostSchedulePriceChangeResponse{PriceChange: *change})
+}
+
+// @Summary List price changes
+// @ID listPriceChanges
+// @Description Get the scheduled price changes of a subscription
+// @Tags subscription
+// @Accept  json
+// @Produce json
+// @Param subscription_id path string true "UUID of the subscription" format(uuid)
+// @Success 200 {object} types.GetListOfPriceChangesResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Subscription not found"
+// @Router /subscriptions/{subscription_id}/price-changes [get]
+func (s *Subscription) getListOfPriceChangesHandler(w http.ResponseWriter, r *http.Request) {
+	subs, err := types.GetSubscriptionByIDHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	changes, err := s.service.GetListOfPriceChanges(r.Context(), subs.SubscriptionID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get price changes", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	types.ProcessError(w, err, &types.GetListOfPriceChangesResponse{PriceChanges: changes})
+}
+
+// @Summary Delete a price change
+// @ID deletePriceChange
+// @Description Cancel a scheduled price change
+// @Tags subscription
+// @Accept  json
+// @Produce json
+// @Param subscription_id path string true "UUID of the subscription" format(uuid)
+// @Param price_change_id path string true "UUID of the price change" format(uuid)
+// @Success 204
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Price change not found"
+// @Router /subscriptions/{subscription_id}/price-changes/{price_change_id} [delete]
+func (s *Subscription) deletePriceChangeByIDHandler(w http.ResponseWriter, r *http.Request) {
+	subID, priceChangeID, err := types.DeletePriceChangeByIDHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	if err := s.service.DeletePriceChangeByID(r.Context(), subID, priceChangeID); err != nil {
+		slog.ErrorContext(r.Context(), "failed to delete price change", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "price change deleted", "price_change_id", priceChangeID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (s *Subscription) WithSubscriptionHandlers(r chi.Router) {
 	r.Post("/subscriptions", s.postCreateSubscriptionHandler)
+	r.Post("/subscriptions/import", s.postImportSubscriptionsHandler)
 	r.Get("/subscriptions/{subscription_id}", s.getSubscriptionByIDHandler)
 	r.Get("/subscriptions", s.getListOfSubscriptionsHandler)
 	r.Get("/subscriptions/total", s.getTotalCostHandler)
+	r.Get("/subscriptions/export", s.getExportSubscriptionsHandler)
+	r.Get("/subscriptions/forecast", s.getForecastHandler)
+	r.Put("/subscriptions/{subscription_id}", s.putReplaceSubscriptionByIDHandler)
 	r.Patch("/subscriptions/{subscription_id}", s.patchSubscriptionByIDHandler)
 	r.Delete("/subscriptions/{subscription_id}", s.deleteSubscriptionByIDHandler)
+	r.Post("/subscriptions/{subscription_id}/restore", s.restoreSubscriptionByIDHandler)
+	r.Post("/subscriptions/{subscription_id}/price-changes", s.postSchedulePriceChangeHandler)
+	r.Get("/subscriptions/{subscription_id}/price-changes", s.getListOfPriceChangesHandler)
+	r.Delete("/subscriptions/{subscription_id}/price-changes/{price_change_id}", s.deletePriceChangeByIDHandler)
 }
@@ -0,0 +1,86 @@
+package types
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ***** [GET] GetSubscriptionHistory *****
+
+func GetSubscriptionHistoryHandlerRequest(r *http.Request) (uuid.UUID, error) {
+	subIDStr := chi.URLParam(r, "subscription_id")
+	subID, err := uuid.Parse(subIDStr)
+	if err != nil {
+		return uuid.Nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	return subID, nil
+}
+
+type GetSubscriptionHistoryResponse struct {
+	Events []domain.SubscriptionEvent `json:"events"`
+}
+
+// ****************************************
+
+// ***** [GET] GetListOfEvents *****
+
+func GetListOfEventsHandlerRequest(r *http.Request) (*domain.SubscriptionEventFilter, error) {
+	q := r.URL.Query()
+	filter := domain.SubscriptionEventFilter{}
+
+	if s := q.Get("subscription_id"); s != "" {
+		parsedUUID, err := uuid.Parse(s)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+		}
+		filter.SubscriptionID = &parsedUUID
+	}
+	if a := q.Get("actor"); a != "" {
+		filter.Actor = &a
+	}
+	if a := q.Get("action"); a != "" {
+		filter.Action = &a
+	}
+	if f := q.Get("from"); f != "" {
+		parsedFrom, err := time.Parse(time.RFC3339, f)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding from: %v", err))
+		}
+		filter.From = &parsedFrom
+	}
+	if t := q.Get("to"); t != "" {
+		parsedTo, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding to: %v", err))
+		}
+		filter.To = &parsedTo
+	}
+	if l := q.Get("limit"); l != "" {
+		parsedLimit, err := strconv.Atoi(l)
+		if err != nil || parsedLimit < 0 {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("invalid limit: %s", l))
+		}
+		filter.Limit = parsedLimit
+	}
+	if o := q.Get("offset"); o != "" {
+		parsedOffset, err := strconv.Atoi(o)
+		if err != nil || parsedOffset < 0 {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("invalid offset: %s", o))
+		}
+		filter.Offset = parsedOffset
+	}
+	return &filter, nil
+}
+
+type GetListOfEventsResponse struct {
+	Events []domain.SubscriptionEvent `json:"events"`
+}
+
+// *********************************
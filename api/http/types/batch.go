@@ -0,0 +1,99 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+// ***** [POST] ApplyBatch *****
+
+type PostApplyBatchRequest struct {
+	// Mode is atomic (default) or best_effort.
+	Mode       string                  `json:"mode"`
+	Operations []BatchOperationRequest `json:"operations"`
+}
+
+type BatchOperationRequest struct {
+	// Op is create, patch or delete.
+	Op string `json:"op"`
+	// SubscriptionID is required for patch and delete.
+	SubscriptionID string `json:"subscription_id,omitempty"`
+	// Subscription has the fields of POST /subscriptions for create and of
+	// PATCH /subscriptions/{id} for patch.
+	Subscription json.RawMessage `json:"subscription,omitempty" swaggertype:"object"`
+}
+
+func PostApplyBatchHandlerRequest(r *http.Request) (*PostApplyBatchRequest, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+
+	var req PostApplyBatchRequest
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
+	}
+	if req.Mode == "" {
+		req.Mode = domain.BatchModeAtomic
+	}
+	return &req, nil
+}
+
+func (r *PostApplyBatchRequest) ToDomain() ([]domain.BatchOperation, error) {
+	ops := make([]domain.BatchOperation, 0, len(r.Operations))
+	for i, op := range r.Operations {
+		sub, err := op.toDomain()
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("operations[%d]: %s", i, errorMessage(err)))
+		}
+		ops = append(ops, domain.BatchOperation{Op: op.Op, Subscription: sub})
+	}
+	return ops, nil
+}
+
+func (op *BatchOperationRequest) toDomain() (*domain.Subscription, error) {
+	if op.Op == domain.BatchOpCreate {
+		var req PostCreateSubscriptionRequest
+		if err := json.Unmarshal(op.Subscription, &req); err != nil {
+			return nil, fmt.Errorf("error while decoding subscription: %v", err)
+		}
+		return req.ToDomain()
+	}
+	if op.Op != domain.BatchOpPatch && op.Op != domain.BatchOpDelete {
+		return nil, fmt.Errorf("unknown operation: %q", op.Op)
+	}
+
+	subID, err := uuid.Parse(op.SubscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding uuid: %v", err)
+	}
+	if op.Op == domain.BatchOpDelete {
+		return &domain.Subscription{SubscriptionID: subID}, nil
+	}
+
+	var req PatchSubscriptionByIDRequest
+	if err := json.Unmarshal(op.Subscription, &req); err != nil {
+		return nil, fmt.Errorf("error while decoding subscription: %v", err)
+	}
+	if req.ServiceName == nil && req.Price == nil && req.EndDate == nil && req.BillingPeriod == nil {
+		return nil, fmt.Errorf("no fields to update")
+	}
+	req.SubscriptionID = subID
+	return req.ToDomain()
+}
+
+type PostApplyBatchResponse struct {
+	Mode      string                        `json:"mode"`
+	Committed bool                          `json:"committed"`
+	Results   []domain.BatchOperationResult `json:"results"`
+}
+
+// ****************************
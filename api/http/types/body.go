@@ -0,0 +1,29 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+// readBody reads the whole request body. Its size is capped by pkgHttp.BodyLimitMiddleware,
+// configured with http.max_body_bytes; uploads such as imports have their own limits.
+func readBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, bodyError(err)
+	}
+	return body, nil
+}
+
+// bodyError tells a body over the size limit apart from other read errors.
+func bodyError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return domain.ErrPayloadTooLarge(fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit))
+	}
+	return domain.ErrBadRequest(fmt.Sprintf("error while reading body: %v", err))
+}
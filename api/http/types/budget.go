@@ -0,0 +1,157 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ***** [POST] CreateBudget *****
+
+type PostCreateBudgetRequest struct {
+	UserID       string  `json:"user_id"`
+	ServiceName  *string `json:"service_name"`
+	MonthlyLimit int     `json:"monthly_limit"`
+}
+
+func CreatePostBudgetHandlerRequest(r *http.Request) (*PostCreateBudgetRequest, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+
+	var req PostCreateBudgetRequest
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
+	}
+	return &req, nil
+}
+
+func (r *PostCreateBudgetRequest) ToDomain() (*domain.Budget, error) {
+	userID, err := uuid.Parse(r.UserID)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	if r.MonthlyLimit < 0 {
+		return nil, domain.ErrBadRequest("monthly_limit cannot be negative")
+	}
+	if r.ServiceName != nil && *r.ServiceName == "" {
+		return nil, domain.ErrBadRequest("service_name cannot be empty")
+	}
+	return &domain.Budget{UserID: userID, ServiceName: r.ServiceName, MonthlyLimit: r.MonthlyLimit}, nil
+}
+
+type PostCreateBudgetResponse struct {
+	Budget domain.Budget `json:"budget"`
+}
+
+// *******************************
+
+// ***** [GET] GetBudgetByID *****
+
+func GetBudgetByIDHandlerRequest(r *http.Request) (uuid.UUID, error) {
+	budgetIDStr := chi.URLParam(r, "budget_id")
+	budgetID, err := uuid.Parse(budgetIDStr)
+	if err != nil {
+		return uuid.Nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	return budgetID, nil
+}
+
+type GetBudgetByIDResponse struct {
+	Budget domain.Budget `json:"budget"`
+}
+
+// *******************************
+
+// ***** [GET] GetListOfBudgets *****
+
+func GetListOfBudgetsHandlerRequest(r *http.Request) (*uuid.UUID, error) {
+	u := r.URL.Query().Get("user_id")
+	if u == "" {
+		return nil, nil
+	}
+	userID, err := uuid.Parse(u)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	return &userID, nil
+}
+
+type GetListOfBudgetsResponse struct {
+	Budgets []domain.Budget `json:"budgets"`
+}
+
+// **********************************
+
+// ***** [PATCH] PatchBudgetByID *****
+
+type PatchBudgetByIDRequest struct {
+	MonthlyLimit *int `json:"monthly_limit"`
+}
+
+func PatchBudgetByIDHandlerRequest(r *http.Request) (*domain.Budget, error) {
+	budgetID, err := GetBudgetByIDHandlerRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+
+	var req PatchBudgetByIDRequest
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
+	}
+	if req.MonthlyLimit == nil {
+		return nil, domain.ErrBadRequest("no fields to update")
+	}
+	if *req.MonthlyLimit < 0 {
+		return nil, domain.ErrBadRequest("monthly_limit cannot be negative")
+	}
+	return &domain.Budget{BudgetID: budgetID, MonthlyLimit: *req.MonthlyLimit}, nil
+}
+
+type PatchBudgetByIDResponse struct {
+	Budget domain.Budget `json:"budget"`
+}
+
+// ***********************************
+
+// ***** [GET] GetBudgetStatus *****
+
+func GetBudgetStatusHandlerRequest(r *http.Request) (uuid.UUID, time.Time, error) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		return uuid.Nil, time.Time{}, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+
+	month := time.Now().UTC()
+	if m := r.URL.Query().Get("month"); m != "" {
+		month, err = parseMonthYear(m)
+		if err != nil {
+			return uuid.Nil, time.Time{}, domain.ErrBadRequest(fmt.Sprintf("error while decoding month: %v", err))
+		}
+	}
+	return userID, month, nil
+}
+
+type GetBudgetStatusResponse struct {
+	Budgets []domain.BudgetStatus `json:"budgets"`
+}
+
+// *********************************
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/ical"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+const defaultCalendarMonths = 12
+
+// ***** [POST] CreateCalendarToken *****
+
+func PostCreateCalendarTokenHandlerRequest(r *http.Request) (uuid.UUID, error) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		return uuid.Nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	return userID, nil
+}
+
+type PostCreateCalendarTokenResponse struct {
+	Token string `json:"token"`
+	// URL is the feed path to subscribe to, relative to the service address.
+	URL string `json:"url"`
+}
+
+// **************************************
+
+// ***** [GET] GetCalendar *****
+
+type GetCalendarRequest struct {
+	UserID uuid.UUID
+	Token  string
+	Months int
+}
+
+func GetCalendarHandlerRequest(r *http.Request) (*GetCalendarRequest, error) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	q := r.URL.Query()
+	req := GetCalendarRequest{UserID: userID, Token: q.Get("token"), Months: defaultCalendarMonths}
+	if req.Token == "" {
+		return nil, domain.ErrUnauthorized("calendar token is required")
+	}
+	if m := q.Get("months"); m != "" {
+		months, err := strconv.Atoi(m)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding months: %v", err))
+		}
+		req.Months = months
+	}
+	return &req, nil
+}
+
+// CalendarFromDomain turns renewals and ends of subscriptions into calendar events. UIDs
+// depend only on the subscription, the kind and the date, so clients update the events
+// they already have instead of duplicating them.
+func CalendarFromDomain(events []domain.CalendarEvent) *ical.Calendar {
+	cal := &ical.Calendar{
+		ProdID: "-//subscription-aggregation-service//calendar//EN",
+		Name:   "Subscriptions",
+		Events: make([]ical.Event, 0, len(events)),
+	}
+	for _, e := range events {
+		sub := e.Subscription
+		event := ical.Event{
+			UID:  ical.UID(sub.SubscriptionID, e.Kind, e.Date.Format("20060102")),
+			Date: e.Date,
+		}
+		if e.Kind == domain.ReminderKindRenewal {
+			event.Summary = fmt.Sprintf("%s renewal: %d", sub.ServiceName, e.Amount)
+			event.Description = fmt.Sprintf("Subscription %s is charged %d (%s billing).",
+				sub.SubscriptionID, e.Amount, sub.BillingPeriod)
+		} else {
+			event.Summary = fmt.Sprintf("%s ends", sub.ServiceName)
+			event.Description = fmt.Sprintf("Subscription %s ends today.", sub.SubscriptionID)
+		}
+		cal.Events = append(cal.Events, event)
+	}
+	return cal
+}
+
+// ****************************
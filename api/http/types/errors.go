@@ -7,6 +7,14 @@ import (
 	"github.com/kasparovgs/subscription-aggregation-service/domain"
 )
 
+// errorMessage returns the message of a domain error without its code prefix.
+func errorMessage(err error) string {
+	if myErr, ok := err.(*domain.MyErr); ok {
+		return myErr.Message
+	}
+	return err.Error()
+}
+
 func ProcessError(w http.ResponseWriter, err error, resp any) {
 	if err != nil {
 		if myErr, ok := err.(*domain.MyErr); ok {
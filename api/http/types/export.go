@@ -0,0 +1,160 @@
+package types
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+)
+
+// exportFlushEvery is how many rows are buffered before they are flushed to the client.
+const exportFlushEvery = 100
+
+// formulaPrefixes are the first characters that make spreadsheet apps read a CSV cell as
+// a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// ***** [GET] ExportSubscriptions *****
+
+func GetExportSubscriptionsHandlerRequest(r *http.Request) (*domain.SubscriptionFilter, string, error) {
+	filter, err := GetListOfSubscriptionsHandlerRequest(r)
+	if err != nil {
+		return nil, "", err
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = ExportFormatCSV
+	}
+	if format != ExportFormatCSV && format != ExportFormatJSONL {
+		return nil, "", domain.ErrBadRequest(fmt.Sprintf("unsupported export format %q", format))
+	}
+	return filter, format, nil
+}
+
+var subscriptionCSVHeader = []string{
+	"subscription_id", "service_name", "price", "user_id", "start_date", "end_date", "billing_period", "deleted_at",
+}
+
+// SubscriptionExporter writes subscriptions to the response one by one as a CSV or JSON Lines
+// attachment. Headers are sent with the first row, so an error that occurs before it can still
+// be reported with a proper status code.
+type SubscriptionExporter struct {
+	w       http.ResponseWriter
+	format  string
+	csv     *csv.Writer
+	json    *json.Encoder
+	rows    int
+	started bool
+}
+
+func NewSubscriptionExporter(w http.ResponseWriter, format string) *SubscriptionExporter {
+	return &SubscriptionExporter{w: w, format: format}
+}
+
+// Started reports whether the response headers have already been sent.
+func (e *SubscriptionExporter) Started() bool {
+	return e.started
+}
+
+func (e *SubscriptionExporter) start() error {
+	e.started = true
+	filename := fmt.Sprintf("subscriptions-%s.%s", time.Now().UTC().Format("20060102"), e.format)
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	if e.format == ExportFormatJSONL {
+		e.w.Header().Set("Content-Type", "application/x-ndjson")
+		e.w.WriteHeader(http.StatusOK)
+		e.json = json.NewEncoder(e.w)
+		return nil
+	}
+	e.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	e.w.WriteHeader(http.StatusOK)
+	e.csv = csv.NewWriter(e.w)
+	return e.csv.Write(subscriptionCSVHeader)
+}
+
+func (e *SubscriptionExporter) Write(sub domain.Subscription) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	var err error
+	if e.format == ExportFormatJSONL {
+		err = e.json.Encode(sub)
+	} else {
+		err = e.csv.Write(subscriptionCSVRecord(sub))
+	}
+	if err != nil {
+		return err
+	}
+	e.rows++
+	if e.rows%exportFlushEvery == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// Close sends whatever is still buffered. An empty export still gets its headers and,
+// for CSV, the header row.
+func (e *SubscriptionExporter) Close() error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
+
+func (e *SubscriptionExporter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+func subscriptionCSVRecord(sub domain.Subscription) []string {
+	var endDate, deletedAt string
+	if sub.EndDate != nil {
+		endDate = sub.EndDate.Format("01-2006")
+	}
+	if sub.DeletedAt != nil {
+		deletedAt = sub.DeletedAt.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		sub.SubscriptionID.String(),
+		csvCell(sub.ServiceName),
+		strconv.Itoa(sub.Price),
+		sub.UserID.String(),
+		sub.StartDate.Format("01-2006"),
+		endDate,
+		sub.BillingPeriod,
+		deletedAt,
+	}
+}
+
+// csvCell quotes a cell that a spreadsheet app would run as a formula, so that an exported
+// service name such as =HYPERLINK(...) opens as text. Import drops the quote again.
+func csvCell(s string) string {
+	if s != "" && strings.IndexByte(formulaPrefixes, s[0]) >= 0 {
+		return "'" + s
+	}
+	return s
+}
+
+// *************************************
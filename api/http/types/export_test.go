@@ -0,0 +1,58 @@
+package types
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestSubscriptionCSVRecordEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		serviceName string
+		want        string
+	}{
+		{"Netflix", "Netflix"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1", "'+1"},
+		{"-1", "'-1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tTab", "'\tTab"},
+		{"\rReturn", "'\rReturn"},
+		{"Yandex=Plus", "Yandex=Plus"},
+		{"'quoted", "'quoted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.serviceName, func(t *testing.T) {
+			sub := domain.Subscription{
+				SubscriptionID: uuid.New(),
+				ServiceName:    tt.serviceName,
+				Price:          799,
+				UserID:         uuid.New(),
+				StartDate:      time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC),
+			}
+			record := subscriptionCSVRecord(sub)
+			if record[1] != tt.want {
+				t.Fatalf("service_name cell %q; want %q", record[1], tt.want)
+			}
+
+			// What was exported imports back unchanged.
+			var buf bytes.Buffer
+			w := csv.NewWriter(&buf)
+			_ = w.Write(subscriptionCSVHeader)
+			_ = w.Write(record)
+			w.Flush()
+			var req PostImportSubscriptionsRequest
+			if err := req.decodeCSV(buf.Bytes()); err != nil || len(req.Rows) != 1 {
+				t.Fatalf("import: %v, %+v", err, req)
+			}
+			if got := req.Rows[0].ServiceName; got != tt.serviceName {
+				t.Fatalf("imported service name %q; want %q", got, tt.serviceName)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package types
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+const defaultForecastMonths = 12
+
+// ***** [GET] GetForecast *****
+
+func GetForecastHandlerRequest(r *http.Request) (*domain.ForecastFilter, error) {
+	q := r.URL.Query()
+	filter := domain.ForecastFilter{From: time.Now().UTC(), Months: defaultForecastMonths}
+
+	if m := q.Get("months"); m != "" {
+		months, err := strconv.Atoi(m)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding months: %v", err))
+		}
+		filter.Months = months
+	}
+	if f := q.Get("from"); f != "" {
+		parsedFrom, err := parseMonthYear(f)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding from: %v", err))
+		}
+		filter.From = parsedFrom
+	}
+	if u := q.Get("user_id"); u != "" {
+		parsedUUID, err := uuid.Parse(u)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+		}
+		filter.UserID = &parsedUUID
+	}
+	if s := q.Get("service_name"); s != "" {
+		filter.ServiceName = &s
+	}
+	for _, value := range q["exclude"] {
+		for _, id := range strings.Split(value, ",") {
+			parsedUUID, err := uuid.Parse(strings.TrimSpace(id))
+			if err != nil {
+				return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding exclude: %v", err))
+			}
+			filter.Exclude = append(filter.Exclude, parsedUUID)
+		}
+	}
+	return &filter, nil
+}
+
+type GetForecastResponse struct {
+	Total  int                    `json:"total"`
+	Months []domain.ForecastMonth `json:"months"`
+}
+
+// *****************************
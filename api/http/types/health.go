@@ -0,0 +1,40 @@
+package types
+
+import "github.com/kasparovgs/subscription-aggregation-service/domain"
+
+// ***** [GET] Liveness *****
+
+type GetLivenessResponse struct {
+	Status string `json:"status"`
+}
+
+// **************************
+
+// ***** [GET] Readiness *****
+
+type HealthCheckResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type GetReadinessResponse struct {
+	Status        string                         `json:"status"`
+	Draining      bool                           `json:"draining"`
+	SchemaVersion uint                           `json:"schema_version"`
+	Checks        map[string]HealthCheckResponse `json:"checks"`
+}
+
+func ReadinessFromDomain(report *domain.HealthReport) *GetReadinessResponse {
+	resp := &GetReadinessResponse{
+		Status:        report.Status,
+		Draining:      report.Draining,
+		SchemaVersion: report.SchemaVersion,
+		Checks:        make(map[string]HealthCheckResponse, len(report.Checks)),
+	}
+	for _, check := range report.Checks {
+		resp.Checks[check.Name] = HealthCheckResponse{Status: check.Status, Error: check.Error}
+	}
+	return resp
+}
+
+// ***************************
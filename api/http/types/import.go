@@ -0,0 +1,189 @@
+package types
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+// maxImportBodySize limits the size of an uploaded import file.
+const maxImportBodySize = 10 << 20
+
+// ***** [POST] ImportSubscriptions *****
+
+// ImportRowError describes why a row of an import was rejected. Rows are numbered from 1,
+// the CSV header line is not counted.
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+type PostImportSubscriptionsRequest struct {
+	DryRun bool
+	Rows   []PostCreateSubscriptionRequest
+	// Errors holds the rows that could not even be decoded.
+	Errors []ImportRowError
+}
+
+// PostImportSubscriptionsHandlerRequest reads a CSV file (Content-Type text/csv) or a JSON
+// array of subscriptions. CSV columns are matched by the header line: service_name, price,
+// user_id, start_date, end_date and billing_period; the last two are optional.
+func PostImportSubscriptionsHandlerRequest(w http.ResponseWriter, r *http.Request) (*PostImportSubscriptionsRequest, error) {
+	req := PostImportSubscriptionsRequest{}
+	if d := r.URL.Query().Get("dry_run"); d != "" {
+		dryRun, err := strconv.ParseBool(d)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding dry_run: %v", err))
+		}
+		req.DryRun = dryRun
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportBodySize))
+	if err != nil {
+		return nil, bodyError(err)
+	}
+	defer r.Body.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		err = req.decodeCSV(body)
+	} else {
+		err = req.decodeJSON(body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (req *PostImportSubscriptionsRequest) decodeJSON(body []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		return domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
+	}
+	for i, item := range items {
+		var row PostCreateSubscriptionRequest
+		if err := json.Unmarshal(item, &row); err != nil {
+			req.Errors = append(req.Errors, ImportRowError{Row: i + 1, Error: err.Error()})
+			continue
+		}
+		req.Rows = append(req.Rows, row)
+	}
+	return nil
+}
+
+func (req *PostImportSubscriptionsRequest) decodeCSV(body []byte) error {
+	reader := csv.NewReader(strings.NewReader(string(body)))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return domain.ErrBadRequest(fmt.Sprintf("error while reading csv header: %v", err))
+	}
+	reader.FieldsPerRecord = len(header)
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{"service_name", "price", "user_id", "start_date"} {
+		if _, ok := columns[name]; !ok {
+			return domain.ErrBadRequest(fmt.Sprintf("csv header has no %s column", name))
+		}
+	}
+
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return domain.ErrBadRequest(fmt.Sprintf("error while reading csv: %v", err))
+			}
+			req.Errors = append(req.Errors, ImportRowError{Row: row, Error: parseErr.Err.Error()})
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		price, err := strconv.Atoi(field("price"))
+		if err != nil {
+			req.Errors = append(req.Errors, ImportRowError{Row: row, Error: fmt.Sprintf("error while decoding price: %v", err)})
+			continue
+		}
+		sub := PostCreateSubscriptionRequest{
+			ServiceName: uncsvCell(field("service_name")),
+			Price:       price,
+			UserID:      field("user_id"),
+			StartDate:   field("start_date"),
+		}
+		if end := field("end_date"); end != "" {
+			sub.EndDate = &end
+		}
+		if period := field("billing_period"); period != "" {
+			sub.BillingPeriod = &period
+		}
+		req.Rows = append(req.Rows, sub)
+	}
+}
+
+// uncsvCell undoes csvCell, so that an export imports back unchanged.
+func uncsvCell(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.IndexByte(formulaPrefixes, s[1]) >= 0 {
+		return s[1:]
+	}
+	return s
+}
+
+// ToDomain validates every decoded row with the same rules as a single create. The returned
+// report lists all rejected rows, including those that failed to decode.
+func (req *PostImportSubscriptionsRequest) ToDomain() ([]*domain.Subscription, []ImportRowError) {
+	rowErrors := append([]ImportRowError(nil), req.Errors...)
+	failed := make(map[int]bool, len(req.Errors))
+	for _, e := range req.Errors {
+		failed[e.Row] = true
+	}
+
+	subs := make([]*domain.Subscription, 0, len(req.Rows))
+	row := 0
+	for i := range req.Rows {
+		row++
+		for failed[row] {
+			row++
+		}
+		sub, err := req.Rows[i].ToDomain()
+		if err != nil {
+			rowErrors = append(rowErrors, ImportRowError{Row: row, Error: errorMessage(err)})
+			continue
+		}
+		subs = append(subs, sub)
+	}
+	sort.Slice(rowErrors, func(i, j int) bool { return rowErrors[i].Row < rowErrors[j].Row })
+	return subs, rowErrors
+}
+
+type PostImportSubscriptionsResponse struct {
+	DryRun          bool             `json:"dry_run"`
+	Total           int              `json:"total"`
+	Imported        int              `json:"imported"`
+	SubscriptionIDs []uuid.UUID      `json:"subscription_ids,omitempty"`
+	Errors          []ImportRowError `json:"errors,omitempty"`
+}
+
+// **************************************
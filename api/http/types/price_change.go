@@ -0,0 +1,80 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ***** [POST] SchedulePriceChange *****
+
+type PostSchedulePriceChangeRequest struct {
+	EffectiveFrom string `json:"effective_from"`
+	Price         *int   `json:"price"`
+}
+
+func PostSchedulePriceChangeHandlerRequest(r *http.Request) (*domain.PriceChange, error) {
+	subID, err := uuid.Parse(chi.URLParam(r, "subscription_id"))
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+
+	var req PostSchedulePriceChangeRequest
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
+	}
+	if req.Price == nil {
+		return nil, domain.ErrBadRequest("price is required")
+	}
+	if *req.Price < 0 {
+		return nil, domain.ErrBadRequest("price cannot be negative")
+	}
+	effectiveFrom, err := parseMonthYear(req.EffectiveFrom)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding effective_from: %v", err))
+	}
+	return &domain.PriceChange{SubscriptionID: subID, EffectiveFrom: effectiveFrom, Price: *req.Price}, nil
+}
+
+type PostSchedulePriceChangeResponse struct {
+	PriceChange domain.PriceChange `json:"price_change"`
+}
+
+// **************************************
+
+// ***** [GET] GetListOfPriceChanges *****
+
+type GetListOfPriceChangesResponse struct {
+	PriceChanges []domain.PriceChange `json:"price_changes"`
+}
+
+// ***************************************
+
+// ***** [DELETE] DeletePriceChangeByID *****
+
+func DeletePriceChangeByIDHandlerRequest(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	subID, err := uuid.Parse(chi.URLParam(r, "subscription_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	priceChangeID, err := uuid.Parse(chi.URLParam(r, "price_change_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	return subID, priceChangeID, nil
+}
+
+// ******************************************
@@ -0,0 +1,164 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/statement"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// maxStatementSize limits the size of an uploaded bank statement.
+const maxStatementSize = 10 << 20
+
+// ***** [POST] ImportStatement *****
+
+type PostImportStatementRequest struct {
+	UserID uuid.UUID
+	Format string
+	Body   io.Reader
+}
+
+// PostImportStatementHandlerRequest takes the format from the format query parameter or,
+// when it is missing, from the Content-Type of the uploaded file.
+func PostImportStatementHandlerRequest(w http.ResponseWriter, r *http.Request) (*PostImportStatementRequest, error) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		switch mediaType {
+		case "text/csv":
+			format = statement.FormatCSV
+		case "application/x-ofx", "application/ofx":
+			format = statement.FormatOFX
+		case "application/vnd.intu.qfx", "application/x-qfx":
+			format = statement.FormatQFX
+		default:
+			return nil, domain.ErrBadRequest("statement format is required: csv, ofx or qfx")
+		}
+	}
+	if format != statement.FormatCSV && format != statement.FormatOFX && format != statement.FormatQFX {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("unsupported statement format %q", format))
+	}
+	return &PostImportStatementRequest{
+		UserID: userID,
+		Format: format,
+		Body:   http.MaxBytesReader(w, r.Body, maxStatementSize),
+	}, nil
+}
+
+type PostImportStatementResponse struct {
+	Candidates []domain.SubscriptionCandidate `json:"candidates"`
+}
+
+// **********************************
+
+// ***** [GET] GetListOfSubscriptionCandidates *****
+
+func GetListOfSubscriptionCandidatesHandlerRequest(r *http.Request) (uuid.UUID, string, error) {
+	userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+	if err != nil {
+		return uuid.Nil, "", domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	return userID, r.URL.Query().Get("status"), nil
+}
+
+type GetListOfSubscriptionCandidatesResponse struct {
+	Candidates []domain.SubscriptionCandidate `json:"candidates"`
+}
+
+// *************************************************
+
+// ***** [POST] ConfirmSubscriptionCandidate *****
+
+// PostConfirmSubscriptionCandidateRequest optionally corrects the detected values before
+// the subscription is created.
+type PostConfirmSubscriptionCandidateRequest struct {
+	CandidateID   uuid.UUID `json:"-"`
+	ServiceName   *string   `json:"service_name,omitempty"`
+	Price         *int      `json:"price,omitempty"`
+	StartDate     *string   `json:"start_date,omitempty"`
+	BillingPeriod *string   `json:"billing_period,omitempty"`
+}
+
+func PostConfirmSubscriptionCandidateHandlerRequest(r *http.Request) (*PostConfirmSubscriptionCandidateRequest, error) {
+	candidateID, err := uuid.Parse(chi.URLParam(r, "candidate_id"))
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+
+	req := PostConfirmSubscriptionCandidateRequest{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
+		}
+	}
+	req.CandidateID = candidateID
+	return &req, nil
+}
+
+func (r *PostConfirmSubscriptionCandidateRequest) ToDomain() (*domain.Subscription, error) {
+	overrides := &domain.Subscription{}
+	if r.ServiceName != nil {
+		if *r.ServiceName == "" {
+			return nil, domain.ErrBadRequest("service_name cannot be empty")
+		}
+		overrides.ServiceName = *r.ServiceName
+	}
+	if r.Price != nil {
+		if *r.Price <= 0 {
+			return nil, domain.ErrBadRequest("price must be positive")
+		}
+		overrides.Price = *r.Price
+	}
+	if r.StartDate != nil {
+		start, err := parseMonthYear(*r.StartDate)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding startDate: %v", err))
+		}
+		overrides.StartDate = start
+	}
+	if r.BillingPeriod != nil {
+		if !domain.IsBillingPeriod(*r.BillingPeriod) {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("unknown billing_period: %q", *r.BillingPeriod))
+		}
+		overrides.BillingPeriod = *r.BillingPeriod
+	}
+	return overrides, nil
+}
+
+type PostConfirmSubscriptionCandidateResponse struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+}
+
+// ***********************************************
+
+// ***** [POST] DismissSubscriptionCandidate *****
+
+func PostDismissSubscriptionCandidateHandlerRequest(r *http.Request) (uuid.UUID, error) {
+	candidateID, err := uuid.Parse(chi.URLParam(r, "candidate_id"))
+	if err != nil {
+		return uuid.Nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	return candidateID, nil
+}
+
+// ***********************************************
@@ -3,7 +3,6 @@ package types
 import (
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -21,6 +20,8 @@ type PostCreateSubscriptionRequest struct {
 	UserID      string  `json:"user_id"`
 	StartDate   string  `json:"start_date"`
 	EndDate     *string `json:"end_date"`
+	// BillingPeriod is monthly (default) or yearly; Price is charged once per period.
+	BillingPeriod *string `json:"billing_period"`
 }
 
 type PostCreateSubscriptionDTO struct {
@@ -50,19 +51,28 @@ func (r *PostCreateSubscriptionRequest) ToDomain() (*domain.Subscription, error)
 		}
 		end = &parsedEnd
 	}
+
+	billingPeriod := domain.BillingPeriodMonthly
+	if r.BillingPeriod != nil {
+		if !domain.IsBillingPeriod(*r.BillingPeriod) {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("unknown billing_period: %q", *r.BillingPeriod))
+		}
+		billingPeriod = *r.BillingPeriod
+	}
 	return &domain.Subscription{
-		ServiceName: r.ServiceName,
-		Price:       r.Price,
-		UserID:      userID,
-		StartDate:   start,
-		EndDate:     end,
+		ServiceName:   r.ServiceName,
+		Price:         r.Price,
+		UserID:        userID,
+		StartDate:     start,
+		EndDate:       end,
+		BillingPeriod: billingPeriod,
 	}, nil
 }
 
 func CreatePostSubscriptionHandlerRequest(r *http.Request) (*PostCreateSubscriptionRequest, error) {
-	body, err := io.ReadAll(r.Body)
+	body, err := readBody(r)
 	if err != nil {
-		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
+		return nil, err
 	}
 
 	defer r.Body.Close()
@@ -100,6 +110,7 @@ type GetSubscriptionByIDResponse struct {
 	UserID         uuid.UUID  `json:"user_id"`
 	StartDate      time.Time  `json:"start_date"`
 	EndDate        *time.Time `json:"end_date"`
+	BillingPeriod  string     `json:"billing_period"`
 }
 
 // *************************************
@@ -111,6 +122,7 @@ type PatchSubscriptionByIDRequest struct {
 	ServiceName    *string   `json:"service_name,omitempty"`
 	Price          *int      `json:"price"`
 	EndDate        *string   `json:"end_date,omitempty"`
+	BillingPeriod  *string   `json:"billing_period,omitempty"`
 }
 
 func PatchSubscriptionByIDHandlerRequest(r *http.Request) (*PatchSubscriptionByIDRequest, error) {
@@ -120,9 +132,9 @@ func PatchSubscriptionByIDHandlerRequest(r *http.Request) (*PatchSubscriptionByI
 		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
 	}
 
-	body, err := io.ReadAll(r.Body)
+	body, err := readBody(r)
 	if err != nil {
-		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
+		return nil, err
 	}
 
 	defer r.Body.Close()
@@ -133,10 +145,11 @@ func PatchSubscriptionByIDHandlerRequest(r *http.Request) (*PatchSubscriptionByI
 	if err != nil {
 		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
 	}
-	if req.ServiceName == nil && req.Price == nil && req.EndDate == nil {
+	if req.ServiceName == nil && req.Price == nil && req.EndDate == nil && req.BillingPeriod == nil {
 		return nil, domain.ErrBadRequest("no fields to update")
 	}
-	return &PatchSubscriptionByIDRequest{SubscriptionID: subID, ServiceName: req.ServiceName, Price: req.Price, EndDate: req.EndDate}, nil
+	return &PatchSubscriptionByIDRequest{SubscriptionID: subID, ServiceName: req.ServiceName, Price: req.Price,
+		EndDate: req.EndDate, BillingPeriod: req.BillingPeriod}, nil
 }
 
 func (r *PatchSubscriptionByIDRequest) ToDomain() (*domain.Subscription, error) {
@@ -159,6 +172,12 @@ func (r *PatchSubscriptionByIDRequest) ToDomain() (*domain.Subscription, error)
 	if end != nil {
 		subs.EndDate = end
 	}
+	if r.BillingPeriod != nil {
+		if !domain.IsBillingPeriod(*r.BillingPeriod) {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("unknown billing_period: %q", *r.BillingPeriod))
+		}
+		subs.BillingPeriod = *r.BillingPeriod
+	}
 	return subs, nil
 }
 
@@ -169,6 +188,90 @@ type PatchSubscriptionByIDResponse struct {
 	UserID         uuid.UUID  `json:"user_id"`
 	StartDate      time.Time  `json:"start_date"`
 	EndDate        *time.Time `json:"end_date"`
+	BillingPeriod  string     `json:"billing_period"`
+}
+
+// *****************************************
+
+// ***** [PUT] ReplaceSubscriptionByID *****
+
+type PutReplaceSubscriptionByIDRequest struct {
+	SubscriptionID uuid.UUID `json:"-"`
+	ServiceName    string    `json:"service_name"`
+	Price          *int      `json:"price"`
+	UserID         string    `json:"user_id"`
+	StartDate      string    `json:"start_date"`
+	EndDate        *string   `json:"end_date"`
+	BillingPeriod  *string   `json:"billing_period"`
+}
+
+func PutReplaceSubscriptionByIDHandlerRequest(r *http.Request) (*PutReplaceSubscriptionByIDRequest, error) {
+	subIDStr := chi.URLParam(r, "subscription_id")
+	subID, err := uuid.Parse(subIDStr)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+
+	var req PutReplaceSubscriptionByIDRequest
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
+	}
+	req.SubscriptionID = subID
+	return &req, nil
+}
+
+func (r *PutReplaceSubscriptionByIDRequest) ToDomain() (*domain.Subscription, error) {
+	if r.ServiceName == "" {
+		return nil, domain.ErrBadRequest("service_name is required")
+	}
+	if r.Price == nil {
+		return nil, domain.ErrBadRequest("price is required")
+	}
+	if *r.Price < 0 {
+		return nil, domain.ErrBadRequest("price cannot be negative")
+	}
+	if r.UserID == "" {
+		return nil, domain.ErrBadRequest("user_id is required")
+	}
+	if r.StartDate == "" {
+		return nil, domain.ErrBadRequest("start_date is required")
+	}
+
+	create := PostCreateSubscriptionRequest{
+		ServiceName:   r.ServiceName,
+		Price:         *r.Price,
+		UserID:        r.UserID,
+		StartDate:     r.StartDate,
+		EndDate:       r.EndDate,
+		BillingPeriod: r.BillingPeriod,
+	}
+	subs, err := create.ToDomain()
+	if err != nil {
+		return nil, err
+	}
+	if subs.EndDate != nil && subs.EndDate.Before(subs.StartDate) {
+		return nil, domain.ErrBadRequest("end date cannot be before start date")
+	}
+	subs.SubscriptionID = r.SubscriptionID
+	return subs, nil
+}
+
+type PutReplaceSubscriptionByIDResponse struct {
+	SubscriptionID uuid.UUID  `json:"subscription_id"`
+	ServiceName    string     `json:"service_name"`
+	Price          int        `json:"price"`
+	UserID         uuid.UUID  `json:"user_id"`
+	StartDate      time.Time  `json:"start_date"`
+	EndDate        *time.Time `json:"end_date"`
+	BillingPeriod  string     `json:"billing_period"`
 }
 
 // *****************************************
@@ -191,6 +294,30 @@ type DeleteSubscriptionByIDResponse struct {
 	UserID         uuid.UUID  `json:"user_id"`
 	StartDate      time.Time  `json:"start_date"`
 	EndDate        *time.Time `json:"end_date"`
+	BillingPeriod  string     `json:"billing_period"`
+}
+
+// *******************************************
+
+// ***** [POST] RestoreSubscriptionByID *****
+func RestoreSubscriptionByIDHandlerRequest(r *http.Request) (*domain.Subscription, error) {
+	subIDStr := chi.URLParam(r, "subscription_id")
+	subID, err := uuid.Parse(subIDStr)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	subs := domain.Subscription{SubscriptionID: subID}
+	return &subs, nil
+}
+
+type RestoreSubscriptionByIDResponse struct {
+	SubscriptionID uuid.UUID  `json:"subscription_id"`
+	ServiceName    string     `json:"service_name"`
+	Price          int        `json:"price"`
+	UserID         uuid.UUID  `json:"user_id"`
+	StartDate      time.Time  `json:"start_date"`
+	EndDate        *time.Time `json:"end_date"`
+	BillingPeriod  string     `json:"billing_period"`
 }
 
 // *******************************************
@@ -234,6 +361,13 @@ func GetListOfSubscriptionsHandlerRequest(r *http.Request) (*domain.Subscription
 	if s := q.Get("service_name"); s != "" {
 		filter.ServiceName = &s
 	}
+	if d := q.Get("include_deleted"); d != "" {
+		includeDeleted, err := strconv.ParseBool(d)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding include_deleted: %v", err))
+		}
+		filter.IncludeDeleted = includeDeleted
+	}
 
 	return &filter, nil
 }
@@ -261,6 +395,13 @@ func GetTotalCostHandlerRequest(r *http.Request) (*domain.TotalCostFilter, error
 	if s := q.Get("service_name"); s != "" {
 		req.ServiceName = &s
 	}
+	if d := q.Get("include_deleted"); d != "" {
+		includeDeleted, err := strconv.ParseBool(d)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding include_deleted: %v", err))
+		}
+		req.IncludeDeleted = includeDeleted
+	}
 
 	if s := q.Get("start_date"); s != "" {
 		parsedStart, err := parseMonthYear(s)
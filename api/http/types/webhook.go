@@ -0,0 +1,197 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// ***** [POST] CreateWebhook *****
+
+type PostCreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	UserID     *string  `json:"user_id"`
+	Secret     string   `json:"secret"`
+}
+
+func CreatePostWebhookHandlerRequest(r *http.Request) (*PostCreateWebhookRequest, error) {
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+
+	var req PostCreateWebhookRequest
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
+	}
+	return &req, nil
+}
+
+func (r *PostCreateWebhookRequest) ToDomain() (*domain.Webhook, error) {
+	if err := validateWebhookURL(r.URL); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEventTypes(r.EventTypes); err != nil {
+		return nil, err
+	}
+
+	var userID *uuid.UUID
+	if r.UserID != nil {
+		parsedUUID, err := uuid.Parse(*r.UserID)
+		if err != nil {
+			return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+		}
+		userID = &parsedUUID
+	}
+	return &domain.Webhook{
+		URL:        r.URL,
+		EventTypes: r.EventTypes,
+		UserID:     userID,
+		Secret:     r.Secret,
+	}, nil
+}
+
+// PostCreateWebhookResponse is the only response that carries the signing secret.
+type PostCreateWebhookResponse struct {
+	WebhookID  uuid.UUID  `json:"webhook_id"`
+	URL        string     `json:"url"`
+	EventTypes []string   `json:"event_types"`
+	UserID     *uuid.UUID `json:"user_id"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"created_at"`
+	Secret     string     `json:"secret"`
+}
+
+// ********************************
+
+// ***** [GET] GetWebhookByID *****
+
+func GetWebhookByIDHandlerRequest(r *http.Request) (uuid.UUID, error) {
+	webhookIDStr := chi.URLParam(r, "webhook_id")
+	webhookID, err := uuid.Parse(webhookIDStr)
+	if err != nil {
+		return uuid.Nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	return webhookID, nil
+}
+
+type GetWebhookByIDResponse struct {
+	Webhook domain.Webhook `json:"webhook"`
+}
+
+// ********************************
+
+// ***** [GET] GetListOfWebhooks *****
+
+type GetListOfWebhooksResponse struct {
+	Webhooks []domain.Webhook `json:"webhooks"`
+}
+
+// ***********************************
+
+// ***** [PATCH] PatchWebhookByID *****
+
+type PatchWebhookByIDRequest struct {
+	URL        *string  `json:"url,omitempty"`
+	EventTypes []string `json:"event_types,omitempty"`
+	Active     *bool    `json:"active,omitempty"`
+}
+
+func PatchWebhookByIDHandlerRequest(r *http.Request) (*domain.WebhookPatch, error) {
+	webhookID, err := GetWebhookByIDHandlerRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+
+	var req PatchWebhookByIDRequest
+	err = json.Unmarshal(body, &req)
+	if err != nil {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding json: %v", err))
+	}
+	if req.URL == nil && req.EventTypes == nil && req.Active == nil {
+		return nil, domain.ErrBadRequest("no fields to update")
+	}
+	if req.URL != nil {
+		if err := validateWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+	}
+	if req.EventTypes != nil {
+		if err := validateWebhookEventTypes(req.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	return &domain.WebhookPatch{WebhookID: webhookID, URL: req.URL, EventTypes: req.EventTypes, Active: req.Active}, nil
+}
+
+type PatchWebhookByIDResponse struct {
+	Webhook domain.Webhook `json:"webhook"`
+}
+
+// ************************************
+
+// ***** [GET] GetListOfWebhookDeliveries *****
+
+type GetListOfWebhookDeliveriesResponse struct {
+	Deliveries []domain.WebhookDelivery `json:"deliveries"`
+}
+
+// ********************************************
+
+// ***** [POST] RedeliverWebhookDelivery *****
+
+func RedeliverWebhookDeliveryHandlerRequest(r *http.Request) (uuid.UUID, uuid.UUID, error) {
+	webhookID, err := GetWebhookByIDHandlerRequest(r)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "delivery_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, domain.ErrBadRequest(fmt.Sprintf("error while decoding uuid: %v", err))
+	}
+	return webhookID, deliveryID, nil
+}
+
+type RedeliverWebhookDeliveryResponse struct {
+	Delivery domain.WebhookDelivery `json:"delivery"`
+}
+
+// *******************************************
+
+func validateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.ErrBadRequest(fmt.Sprintf("invalid webhook url: %q", raw))
+	}
+	return nil
+}
+
+func validateWebhookEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return domain.ErrBadRequest("event_types must not be empty")
+	}
+	for _, t := range eventTypes {
+		if !domain.IsWebhookEventType(t) {
+			return domain.ErrBadRequest(fmt.Sprintf("unknown event type: %q", t))
+		}
+	}
+	return nil
+}
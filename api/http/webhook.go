@@ -0,0 +1,224 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/usecases"
+
+	"github.com/kasparovgs/subscription-aggregation-service/api/http/types"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Webhook represents an HTTP handler for managing webhook endpoints.
+type Webhook struct {
+	service usecases.Webhook
+}
+
+// NewWebhookHandler creates a new instance of Webhook.
+func NewWebhookHandler(service usecases.Webhook) *Webhook {
+	return &Webhook{service: service}
+}
+
+// @Summary Register a webhook
+// @ID createWebhook
+// @Description Register an endpoint for signed event deliveries. The signing secret is returned only once
+// @Tags webhook
+// @Accept  json
+// @Produce json
+// @Param request body types.PostCreateWebhookRequest true "Endpoint and event types"
+// @Success 201 {object} types.PostCreateWebhookResponse
+// @Failure 400 {string} string "Bad request"
+// @Router /webhooks [post]
+func (h *Webhook) postCreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := types.CreatePostWebhookHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	webhook, err := req.ToDomain()
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to convert request to domain", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	webhook, err = h.service.CreateWebhook(r.Context(), webhook)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to create webhook in service", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "webhook created", "webhook_id", webhook.WebhookID)
+	types.ProcessError(w, err, &types.PostCreateWebhookResponse{WebhookID: webhook.WebhookID,
+		URL: webhook.URL, EventTypes: webhook.EventTypes, UserID: webhook.UserID, Active: webhook.Active,
+		CreatedAt: webhook.CreatedAt, Secret: webhook.Secret})
+}
+
+// @Summary Get a webhook
+// @ID getWebhook
+// @Description Get a webhook by their webhookID
+// @Tags webhook
+// @Accept  json
+// @Produce json
+// @Param webhook_id path string true "UUID of the webhook" format(uuid)
+// @Success 200 {object} types.GetWebhookByIDResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Webhook not found"
+// @Router /webhooks/{webhook_id} [get]
+func (h *Webhook) getWebhookByIDHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := types.GetWebhookByIDHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	webhook, err := h.service.GetWebhookByID(r.Context(), webhookID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get webhook by webhookID", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	types.ProcessError(w, err, &types.GetWebhookByIDResponse{Webhook: *webhook})
+}
+
+// @Summary List webhooks
+// @ID listWebhooks
+// @Description Get all registered webhooks
+// @Tags webhook
+// @Accept  json
+// @Produce json
+// @Success 200 {object} types.GetListOfWebhooksResponse
+// @Router /webhooks [get]
+func (h *Webhook) getListOfWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.service.GetListOfWebhooks(r.Context())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get list of webhooks", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	types.ProcessError(w, err, &types.GetListOfWebhooksResponse{Webhooks: webhooks})
+}
+
+// @Summary Patch a webhook
+// @ID patchWebhook
+// @Description Change the url, event types or active flag of a webhook
+// @Tags webhook
+// @Accept  json
+// @Produce json
+// @Param webhook_id path string true "UUID of the webhook" format(uuid)
+// @Param request body types.PatchWebhookByIDRequest true "Fields to update"
+// @Success 200 {object} types.PatchWebhookByIDResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Webhook not found"
+// @Router /webhooks/{webhook_id} [patch]
+func (h *Webhook) patchWebhookByIDHandler(w http.ResponseWriter, r *http.Request) {
+	patch, err := types.PatchWebhookByIDHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	webhook, err := h.service.PatchWebhookByID(r.Context(), patch)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to patch webhook by webhookID", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "webhook patched", "webhook_id", webhook.WebhookID)
+	types.ProcessError(w, err, &types.PatchWebhookByIDResponse{Webhook: *webhook})
+}
+
+// @Summary Delete a webhook
+// @ID deleteWebhook
+// @Description Delete a webhook and its delivery log
+// @Tags webhook
+// @Accept  json
+// @Produce json
+// @Param webhook_id path string true "UUID of the webhook" format(uuid)
+// @Success 204
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Webhook not found"
+// @Router /webhooks/{webhook_id} [delete]
+func (h *Webhook) deleteWebhookByIDHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := types.GetWebhookByIDHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	if err := h.service.DeleteWebhookByID(r.Context(), webhookID); err != nil {
+		slog.ErrorContext(r.Context(), "failed to delete webhook by webhookID", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "webhook deleted", "webhook_id", webhookID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List webhook deliveries
+// @ID listWebhookDeliveries
+// @Description Get the delivery log of a webhook, newest first
+// @Tags webhook
+// @Accept  json
+// @Produce json
+// @Param webhook_id path string true "UUID of the webhook" format(uuid)
+// @Success 200 {object} types.GetListOfWebhookDeliveriesResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Webhook not found"
+// @Router /webhooks/{webhook_id}/deliveries [get]
+func (h *Webhook) getListOfWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := types.GetWebhookByIDHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	deliveries, err := h.service.GetListOfWebhookDeliveries(r.Context(), webhookID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to get webhook deliveries", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	types.ProcessError(w, err, &types.GetListOfWebhookDeliveriesResponse{Deliveries: deliveries})
+}
+
+// @Summary Redeliver a webhook delivery
+// @ID redeliverWebhookDelivery
+// @Description Queue the payload of an earlier delivery again
+// @Tags webhook
+// @Accept  json
+// @Produce json
+// @Param webhook_id path string true "UUID of the webhook" format(uuid)
+// @Param delivery_id path string true "UUID of the delivery" format(uuid)
+// @Success 200 {object} types.RedeliverWebhookDeliveryResponse
+// @Failure 400 {string} string "Bad request"
+// @Failure 404 {string} string "Webhook delivery not found"
+// @Router /webhooks/{webhook_id}/deliveries/{delivery_id}/redeliver [post]
+func (h *Webhook) redeliverWebhookDeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	webhookID, deliveryID, err := types.RedeliverWebhookDeliveryHandlerRequest(r)
+	if err != nil {
+		slog.WarnContext(r.Context(), "failed to parse request", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	delivery, err := h.service.RedeliverWebhookDelivery(r.Context(), webhookID, deliveryID)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to redeliver webhook delivery", "error", err)
+		types.ProcessError(w, err, nil)
+		return
+	}
+	slog.InfoContext(r.Context(), "webhook delivery queued for redelivery", "delivery_id", delivery.DeliveryID)
+	types.ProcessError(w, err, &types.RedeliverWebhookDeliveryResponse{Delivery: *delivery})
+}
+
+func (h *Webhook) WithWebhookHandlers(r chi.Router) {
+	r.Post("/webhooks", h.postCreateWebhookHandler)
+	r.Get("/webhooks", h.getListOfWebhooksHandler)
+	r.Get("/webhooks/{webhook_id}", h.getWebhookByIDHandler)
+	r.Patch("/webhooks/{webhook_id}", h.patchWebhookByIDHandler)
+	r.Delete("/webhooks/{webhook_id}", h.deleteWebhookByIDHandler)
+	r.Get("/webhooks/{webhook_id}/deliveries", h.getListOfWebhookDeliveriesHandler)
+	r.Post("/webhooks/{webhook_id}/deliveries/{delivery_id}/redeliver", h.redeliverWebhookDeliveryHandler)
+}
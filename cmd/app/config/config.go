@@ -1,25 +1,228 @@
 package config
 
-import "flag"
+import (
+	"flag"
+	"net/netip"
+	"strings"
+	"time"
+)
 
 type AppFlags struct {
-	ConfigPath string
+	ConfigPath  string
+	PrintConfig bool
+	// Args are the arguments after the flags, e.g. migrate up.
+	Args []string
 }
 
 func ParseFlags() AppFlags {
 	configPath := flag.String("config", "", "Path to config")
+	printConfig := flag.Bool("print-config", false, "Print the effective config with secrets redacted and exit")
 	flag.Parse()
 	return AppFlags{
-		ConfigPath: *configPath,
+		ConfigPath:  *configPath,
+		PrintConfig: *printConfig,
+		Args:        flag.Args(),
 	}
 }
 
 type HTTPConfig struct {
-	Address string `env:"APP_PORT"`
+	Address           string        `env:"APP_PORT" env-default:"8080"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" env-default:"5s"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" env-default:"30s"`
+	// WriteTimeout does not apply to exports, which stream for as long as they need.
+	WriteTimeout   time.Duration `yaml:"write_timeout" env-default:"60s"`
+	IdleTimeout    time.Duration `yaml:"idle_timeout" env-default:"120s"`
+	MaxHeaderBytes int           `yaml:"max_header_bytes" env-default:"65536"`
+	// MaxBodyBytes caps every request body, JSON bodies included; uploads such as imports are
+	// further limited to 10 MiB.
+	MaxBodyBytes int64      `yaml:"max_body_bytes" env-default:"16777216"`
+	TLS          TLSConfig  `yaml:"tls"`
+	CORS         CORSConfig `yaml:"cors"`
+}
+
+// TLSConfig enables HTTPS when both files are set.
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile  string `yaml:"key_file" env:"TLS_KEY_FILE"`
+}
+
+// CORSConfig is disabled while AllowedOrigins is empty.
+type CORSConfig struct {
+	AllowedOrigins   []string `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	AllowedMethods   []string `yaml:"allowed_methods" env-default:"GET,POST,PUT,PATCH,DELETE"`
+	AllowedHeaders   []string `yaml:"allowed_headers" env-default:"Content-Type,X-Actor,X-Request-ID"`
+	ExposedHeaders   []string `yaml:"exposed_headers" env-default:"X-Request-ID,RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,RateLimit-Policy,Retry-After"`
+	AllowCredentials bool     `yaml:"allow_credentials"`
+	MaxAge           int      `yaml:"max_age" env-default:"600"`
+}
+
+// DBConfig describes the PostgreSQL connection. Fields tagged secret are redacted by
+// --print-config.
+type DBConfig struct {
+	// DSN replaces the connection pieces below when set.
+	DSN      string `yaml:"dsn" env:"DB_CONN_STR" secret:"true"`
+	Host     string `yaml:"host" env:"DB_HOST" env-default:"localhost"`
+	Port     int    `yaml:"port" env:"DB_PORT" env-default:"5432"`
+	Name     string `yaml:"name" env:"DB_NAME"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `env:"DB_PASS" secret:"true"`
+	SSLMode  string `yaml:"ssl_mode" env:"DB_SSLMODE" env-default:"disable"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" env-default:"25"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" env-default:"25"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" env-default:"30m"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" env-default:"5m"`
+	// StatementTimeout is set as the session statement_timeout; 0 disables it.
+	StatementTimeout time.Duration `yaml:"statement_timeout" env:"DB_STATEMENT_TIMEOUT" env-default:"30s"`
+
+	// ConnectTimeout is how long startup keeps retrying until the database accepts connections.
+	ConnectTimeout    time.Duration `yaml:"connect_timeout" env:"DB_CONNECT_TIMEOUT" env-default:"1m"`
+	ConnectBackoff    time.Duration `yaml:"connect_backoff" env-default:"500ms"`
+	ConnectMaxBackoff time.Duration `yaml:"connect_max_backoff" env-default:"5s"`
+	// ReadRetries repeats reads outside transactions after transient connection errors.
+	ReadRetries      int           `yaml:"read_retries" env-default:"2"`
+	ReadRetryBackoff time.Duration `yaml:"read_retry_backoff" env-default:"100ms"`
+}
+
+type MigrationsConfig struct {
+	// Auto applies pending migrations on startup. Instances starting together take turns
+	// through an advisory lock.
+	Auto    bool          `yaml:"auto" env:"AUTO_MIGRATE"`
+	Timeout time.Duration `yaml:"timeout" env-default:"5m"`
+}
+
+type GRPCConfig struct {
+	Enabled bool   `yaml:"enabled" env:"GRPC_ENABLED" env-default:"true"`
+	Address string `yaml:"address" env:"GRPC_PORT" env-default:"9090"`
+}
+
+type GraphQLConfig struct {
+	MaxDepth      int `yaml:"max_depth" env-default:"8"`
+	MaxComplexity int `yaml:"max_complexity" env-default:"1000"`
+	// MaxIntrospectionDepth bounds __schema and __type selections, which nest deeper than
+	// data queries.
+	MaxIntrospectionDepth int `yaml:"max_introspection_depth" env-default:"15"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED" env-default:"true"`
+	// TrustedProxies are the addresses or CIDRs of the proxies in front of the service. The
+	// client address is taken from X-Forwarded-For only on requests coming from them.
+	TrustedProxies []string `yaml:"trusted_proxies" env:"RATE_LIMIT_TRUSTED_PROXIES"`
+	// AddressLimit bounds all requests from one client address, whatever API key or user they
+	// name; the other limits apply per client on top of it.
+	AddressLimit LimitConfig `yaml:"address"`
+	// Default is shared by all routes without a limit of their own.
+	Default LimitConfig `yaml:"default"`
+	// Routes are keyed by method and chi pattern, e.g. "GET /subscriptions/total". A limit
+	// of 0 requests disables limiting for the route.
+	Routes map[string]LimitConfig `yaml:"routes"`
+}
+
+// TrustedProxyPrefixes parses TrustedProxies; a single address stands for itself.
+func (c RateLimitConfig) TrustedProxyPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(c.TrustedProxies))
+	for _, proxy := range c.TrustedProxies {
+		if !strings.Contains(proxy, "/") {
+			addr, err := netip.ParseAddr(proxy)
+			if err != nil {
+				return nil, err
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+type LimitConfig struct {
+	Requests int           `yaml:"requests" env-default:"300"`
+	Period   time.Duration `yaml:"period" env-default:"1m"`
+	Burst    int           `yaml:"burst" env-default:"100"`
+}
+
+type HealthConfig struct {
+	// CheckTimeout bounds the dependency checks of a single readiness probe.
+	CheckTimeout time.Duration `yaml:"check_timeout" env-default:"2s"`
+	// DrainDelay is how long readiness fails before the server stops accepting requests,
+	// giving load balancers time to notice.
+	DrainDelay time.Duration `yaml:"drain_delay" env-default:"5s"`
+}
+
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled" env:"METRICS_ENABLED" env-default:"true"`
+	Path    string `yaml:"path" env-default:"/metrics"`
+	// Interval is how often the business gauges are recomputed from the database.
+	Interval time.Duration `yaml:"interval" env-default:"30s"`
+}
+
+type TracingConfig struct {
+	// Exporter is one of file, stdout or none. stdout shares the stream with the logs,
+	// so it is only meant for local runs.
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" env-default:"file"`
+	FilePath    string  `yaml:"file_path" env:"TRACING_FILE_PATH" env-default:"traces.jsonl"`
+	SampleRatio float64 `yaml:"sample_ratio" env-default:"1"`
 }
 
 type LoggerConfig struct {
 	Level string `yaml:"level"`
+	// Format is text or json.
+	Format string `yaml:"format" env:"LOG_FORMAT" env-default:"text"`
+}
+
+type PurgeConfig struct {
+	Retention time.Duration `yaml:"retention" env-default:"2160h"`
+	Interval  time.Duration `yaml:"interval" env-default:"1h"`
+}
+
+type OutboxConfig struct {
+	// Publisher is one of file, stdout or webhook. stdout shares the stream with the logs,
+	// so it is only meant for local runs.
+	Publisher      string        `yaml:"publisher" env:"OUTBOX_PUBLISHER" env-default:"file"`
+	FilePath       string        `yaml:"file_path" env:"OUTBOX_FILE_PATH" env-default:"events.jsonl"`
+	WebhookURL     string        `yaml:"webhook_url" env:"OUTBOX_WEBHOOK_URL"`
+	WebhookTimeout time.Duration `yaml:"webhook_timeout" env-default:"5s"`
+	Interval       time.Duration `yaml:"interval" env-default:"1s"`
+	BatchSize      int           `yaml:"batch_size" env-default:"100"`
+	Lease          time.Duration `yaml:"lease" env-default:"30s"`
+	MaxBackoff     time.Duration `yaml:"max_backoff" env-default:"5m"`
+}
+
+type WebhookConfig struct {
+	Timeout     time.Duration `yaml:"timeout" env-default:"10s"`
+	Interval    time.Duration `yaml:"interval" env-default:"1s"`
+	BatchSize   int           `yaml:"batch_size" env-default:"50"`
+	Lease       time.Duration `yaml:"lease" env-default:"1m"`
+	MaxAttempts int           `yaml:"max_attempts" env-default:"8"`
+	MaxBackoff  time.Duration `yaml:"max_backoff" env-default:"1h"`
+}
+
+type ReminderConfig struct {
+	Interval   time.Duration `yaml:"interval" env-default:"1h"`
+	WindowDays int           `yaml:"window_days" env-default:"7"`
+	// Notifiers lists where reminders go: log, smtp and/or webhook.
+	Notifiers []string   `yaml:"notifiers" env-default:"log"`
+	SMTP      SMTPConfig `yaml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string   `yaml:"host" env:"SMTP_HOST"`
+	Port     int      `yaml:"port" env:"SMTP_PORT" env-default:"25"`
+	Username string   `yaml:"username" env:"SMTP_USERNAME"`
+	Password string   `env:"SMTP_PASSWORD" secret:"true"`
+	From     string   `yaml:"from" env:"SMTP_FROM"`
+	To       []string `yaml:"to"`
+	// Timeout bounds connecting to the server and the whole exchange with it.
+	Timeout time.Duration `yaml:"timeout" env-default:"30s"`
+}
+
+type BudgetConfig struct {
+	Interval time.Duration `yaml:"interval" env-default:"6h"`
 }
 
 type AppInfo struct {
@@ -28,7 +231,20 @@ type AppInfo struct {
 }
 
 type AppConfig struct {
-	AppInfo `yaml:"app"`
-	HTTPConfig
-	LoggerConfig `yaml:"logger"`
+	AppInfo          `yaml:"app"`
+	HTTPConfig       `yaml:"http"`
+	DBConfig         DBConfig `yaml:"database"`
+	MigrationsConfig `yaml:"migrations"`
+	GRPCConfig       GRPCConfig `yaml:"grpc"`
+	GraphQLConfig    `yaml:"graphql"`
+	RateLimitConfig  `yaml:"rate_limit"`
+	HealthConfig     `yaml:"health"`
+	MetricsConfig    `yaml:"metrics"`
+	TracingConfig    `yaml:"tracing"`
+	LoggerConfig     `yaml:"logger"`
+	PurgeConfig      `yaml:"purge"`
+	OutboxConfig     `yaml:"outbox"`
+	WebhookConfig    `yaml:"webhooks"`
+	ReminderConfig   `yaml:"reminders"`
+	BudgetConfig     `yaml:"budgets"`
 }
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate checks the whole config and reports every problem at once, each prefixed with
+// the yaml path of the offending setting.
+func (c *AppConfig) Validate() error {
+	v := &validator{}
+
+	v.address("http.address (APP_PORT)", c.HTTPConfig.Address)
+	v.nonNegative("http.read_header_timeout", c.HTTPConfig.ReadHeaderTimeout)
+	v.nonNegative("http.read_timeout", c.HTTPConfig.ReadTimeout)
+	v.nonNegative("http.write_timeout", c.HTTPConfig.WriteTimeout)
+	v.nonNegative("http.idle_timeout", c.HTTPConfig.IdleTimeout)
+	v.check(c.HTTPConfig.MaxHeaderBytes > 0, "http.max_header_bytes", "must be positive")
+	v.check(c.HTTPConfig.MaxBodyBytes > 0, "http.max_body_bytes", "must be positive")
+	v.check((c.HTTPConfig.TLS.CertFile == "") == (c.HTTPConfig.TLS.KeyFile == ""),
+		"http.tls", "cert_file and key_file must be set together")
+	v.check(c.HTTPConfig.CORS.MaxAge >= 0, "http.cors.max_age", "must not be negative")
+	v.check(!c.HTTPConfig.CORS.AllowCredentials || !slices.Contains(c.HTTPConfig.CORS.AllowedOrigins, "*"),
+		"http.cors.allow_credentials", "cannot be used with the * origin")
+
+	c.DBConfig.validate(v)
+	v.positive("migrations.timeout", c.MigrationsConfig.Timeout)
+
+	if c.GRPCConfig.Enabled {
+		v.address("grpc.address (GRPC_PORT)", c.GRPCConfig.Address)
+		v.check(c.GRPCConfig.Address != c.HTTPConfig.Address, "grpc.address", "must differ from http.address")
+	}
+
+	v.check(c.GraphQLConfig.MaxDepth > 0, "graphql.max_depth", "must be positive")
+	v.check(c.GraphQLConfig.MaxComplexity > 0, "graphql.max_complexity", "must be positive")
+	v.check(c.GraphQLConfig.MaxIntrospectionDepth > 0, "graphql.max_introspection_depth", "must be positive")
+
+	if c.RateLimitConfig.Enabled {
+		_, err := c.RateLimitConfig.TrustedProxyPrefixes()
+		v.check(err == nil, "rate_limit.trusted_proxies", fmt.Sprintf("must list addresses or CIDRs: %v", err))
+		v.limit("rate_limit.address", c.RateLimitConfig.AddressLimit)
+		v.limit("rate_limit.default", c.RateLimitConfig.Default)
+		for route, limit := range c.RateLimitConfig.Routes {
+			name := fmt.Sprintf("rate_limit.routes[%q]", route)
+			method, pattern, ok := strings.Cut(route, " ")
+			v.check(ok && method == strings.ToUpper(method) && strings.HasPrefix(pattern, "/"),
+				name, `must be keyed by method and route pattern, e.g. "GET /subscriptions"`)
+			v.limit(name, limit)
+		}
+	}
+
+	v.positive("health.check_timeout", c.HealthConfig.CheckTimeout)
+	v.nonNegative("health.drain_delay", c.HealthConfig.DrainDelay)
+
+	if c.MetricsConfig.Enabled {
+		v.check(strings.HasPrefix(c.MetricsConfig.Path, "/"), "metrics.path", "must start with /")
+		v.positive("metrics.interval", c.MetricsConfig.Interval)
+	}
+
+	v.oneOf("tracing.exporter", c.TracingConfig.Exporter, "stdout", "file", "none")
+	v.check(c.TracingConfig.Exporter != "file" || c.TracingConfig.FilePath != "",
+		"tracing.file_path", "is required for the file exporter")
+	v.check(c.TracingConfig.SampleRatio >= 0 && c.TracingConfig.SampleRatio <= 1,
+		"tracing.sample_ratio", "must be between 0 and 1")
+
+	v.oneOf("logger.level", strings.ToLower(c.LoggerConfig.Level), "", "debug", "info", "warn", "error")
+	v.oneOf("logger.format", c.LoggerConfig.Format, "text", "json")
+
+	v.positive("purge.retention", c.PurgeConfig.Retention)
+	v.positive("purge.interval", c.PurgeConfig.Interval)
+
+	v.oneOf("outbox.publisher", c.OutboxConfig.Publisher, "stdout", "file", "webhook")
+	v.check(c.OutboxConfig.Publisher != "file" || c.OutboxConfig.FilePath != "",
+		"outbox.file_path", "is required for the file publisher")
+	v.check(c.OutboxConfig.Publisher != "webhook" || c.OutboxConfig.WebhookURL != "",
+		"outbox.webhook_url", "is required for the webhook publisher")
+	v.positive("outbox.webhook_timeout", c.OutboxConfig.WebhookTimeout)
+	v.positive("outbox.interval", c.OutboxConfig.Interval)
+	v.check(c.OutboxConfig.BatchSize > 0, "outbox.batch_size", "must be positive")
+	v.positive("outbox.lease", c.OutboxConfig.Lease)
+	v.positive("outbox.max_backoff", c.OutboxConfig.MaxBackoff)
+
+	v.positive("webhooks.timeout", c.WebhookConfig.Timeout)
+	v.positive("webhooks.interval", c.WebhookConfig.Interval)
+	v.check(c.WebhookConfig.BatchSize > 0, "webhooks.batch_size", "must be positive")
+	v.positive("webhooks.lease", c.WebhookConfig.Lease)
+	v.check(c.WebhookConfig.MaxAttempts > 0, "webhooks.max_attempts", "must be positive")
+	v.positive("webhooks.max_backoff", c.WebhookConfig.MaxBackoff)
+
+	v.positive("reminders.interval", c.ReminderConfig.Interval)
+	v.check(c.ReminderConfig.WindowDays > 0, "reminders.window_days", "must be positive")
+	for _, name := range c.ReminderConfig.Notifiers {
+		v.oneOf("reminders.notifiers", name, "log", "smtp", "webhook")
+	}
+	if slices.Contains(c.ReminderConfig.Notifiers, "smtp") {
+		smtp := c.ReminderConfig.SMTP
+		v.check(smtp.Host != "", "reminders.smtp.host", "is required for the smtp notifier")
+		v.check(smtp.Port > 0 && smtp.Port <= 65535, "reminders.smtp.port", "must be between 1 and 65535")
+		v.check(smtp.From != "", "reminders.smtp.from", "is required for the smtp notifier")
+		v.check(len(smtp.To) > 0, "reminders.smtp.to", "is required for the smtp notifier")
+		v.positive("reminders.smtp.timeout", smtp.Timeout)
+	}
+
+	v.positive("budgets.interval", c.BudgetConfig.Interval)
+
+	return errors.Join(v.errs...)
+}
+
+func (c *DBConfig) validate(v *validator) {
+	if c.DSN == "" {
+		v.check(c.Host != "", "database.host (DB_HOST)", "is required unless database.dsn is set")
+		v.check(c.Port > 0 && c.Port <= 65535, "database.port (DB_PORT)", "must be between 1 and 65535")
+		v.check(c.Name != "", "database.name (DB_NAME)", "is required unless database.dsn is set")
+		v.check(c.User != "", "database.user (DB_USER)", "is required unless database.dsn is set")
+		v.oneOf("database.ssl_mode (DB_SSLMODE)", c.SSLMode,
+			"disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	}
+	v.check(c.MaxOpenConns >= 0, "database.max_open_conns", "must not be negative")
+	v.check(c.MaxIdleConns >= 0, "database.max_idle_conns", "must not be negative")
+	v.check(c.MaxOpenConns == 0 || c.MaxIdleConns <= c.MaxOpenConns,
+		"database.max_idle_conns", "must not exceed max_open_conns")
+	v.nonNegative("database.conn_max_lifetime", c.ConnMaxLifetime)
+	v.nonNegative("database.conn_max_idle_time", c.ConnMaxIdleTime)
+	v.nonNegative("database.statement_timeout", c.StatementTimeout)
+	v.positive("database.connect_timeout", c.ConnectTimeout)
+	v.positive("database.connect_backoff", c.ConnectBackoff)
+	v.check(c.ConnectMaxBackoff >= c.ConnectBackoff, "database.connect_max_backoff", "must not be less than connect_backoff")
+	v.check(c.ReadRetries >= 0, "database.read_retries", "must not be negative")
+	v.check(c.ReadRetries == 0 || c.ReadRetryBackoff > 0, "database.read_retry_backoff", "must be positive")
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) check(ok bool, name, problem string) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s: %s", name, problem))
+	}
+}
+
+func (v *validator) positive(name string, d time.Duration) {
+	v.check(d > 0, name, "must be positive")
+}
+
+func (v *validator) nonNegative(name string, d time.Duration) {
+	v.check(d >= 0, name, "must not be negative")
+}
+
+func (v *validator) oneOf(name, value string, allowed ...string) {
+	v.check(slices.Contains(allowed, value), name,
+		fmt.Sprintf("%q is not one of %s", value, strings.Join(allowed[nonEmpty(allowed):], ", ")))
+}
+
+func (v *validator) address(name, addr string) {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		v.check(false, name, err.Error())
+		return
+	}
+	if port == "" {
+		v.check(false, name, "is required")
+		return
+	}
+	n, err := strconv.Atoi(port)
+	v.check(err == nil && n > 0 && n <= 65535, name, fmt.Sprintf("invalid port %q", port))
+}
+
+func (v *validator) limit(name string, l LimitConfig) {
+	v.check(l.Requests >= 0, name+".requests", "must not be negative")
+	v.check(l.Burst >= 0, name+".burst", "must not be negative")
+	v.check(l.Requests == 0 || l.Period > 0, name+".period", "must be positive")
+}
+
+// nonEmpty skips a leading empty value, which stands for "unset" and is not worth listing.
+func nonEmpty(allowed []string) int {
+	if len(allowed) > 0 && allowed[0] == "" {
+		return 1
+	}
+	return 0
+}
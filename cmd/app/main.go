@@ -2,14 +2,20 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"net"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
+	graphqlApi "github.com/kasparovgs/subscription-aggregation-service/api/graphql"
+	grpcApi "github.com/kasparovgs/subscription-aggregation-service/api/grpc"
 	"github.com/kasparovgs/subscription-aggregation-service/api/http"
 
+	"github.com/kasparovgs/subscription-aggregation-service/migrations"
 	"github.com/kasparovgs/subscription-aggregation-service/repository/postgres_storage"
 
 	"github.com/kasparovgs/subscription-aggregation-service/usecases/service"
@@ -19,6 +25,12 @@ import (
 	"github.com/kasparovgs/subscription-aggregation-service/pkg/config"
 	pkgHttp "github.com/kasparovgs/subscription-aggregation-service/pkg/http"
 	"github.com/kasparovgs/subscription-aggregation-service/pkg/logger"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/notifier"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/publisher"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/ratelimit"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/tracing"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/webhook"
 
 	_ "github.com/kasparovgs/subscription-aggregation-service/docs"
 
@@ -26,6 +38,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
 )
 
 // @title My API
@@ -38,21 +51,47 @@ func main() {
 
 	appFlags := appConfig.ParseFlags()
 	var cfg appConfig.AppConfig
-	config.MustLoad(appFlags.ConfigPath, &cfg)
+	if err := config.Load(appFlags.ConfigPath, &cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if appFlags.PrintConfig {
+		if err := config.Print(os.Stdout, &cfg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	logger.Init(&cfg)
 
+	if len(appFlags.Args) > 0 {
+		if err := runCommand(cfg, appFlags.Args); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	slog.Info("starting service", "name", cfg.Name, "version", cfg.Version)
 
 	slog.Info("config loaded", "config_path", appFlags.ConfigPath)
 
-	connStr := os.Getenv("DB_CONN_STR")
-	if connStr == "" {
-		slog.Error("DB_CONN_STR environment variable is required")
+	shutdownTracing, err := tracing.Init(tracing.Config{
+		Exporter:    cfg.TracingConfig.Exporter,
+		FilePath:    cfg.TracingConfig.FilePath,
+		SampleRatio: cfg.TracingConfig.SampleRatio,
+		ServiceName: cfg.Name,
+		Version:     cfg.Version,
+	})
+	if err != nil {
+		slog.Error("failed to initialize tracing", "error", err)
 		os.Exit(1)
 	}
 
-	subscriptionRepo, err := postgres_storage.NewSubscriptionDB(connStr)
+	dbConfig := postgresConfig(cfg.DBConfig)
+	subscriptionRepo, err := postgres_storage.NewSubscriptionDB(dbConfig)
 	if err != nil {
 		slog.Error("no connection with postgres", "error", err)
 		os.Exit(1)
@@ -64,29 +103,203 @@ func main() {
 		}
 	}()
 
-	slog.Info("connected to postgres")
+	slog.Info("connected to postgres", "database", dbConfig.String())
+
+	migrator, err := postgres_storage.NewMigrator(subscriptionRepo.Pool(), migrations.FS)
+	if err != nil {
+		slog.Error("failed to load migrations", "error", err)
+		os.Exit(1)
+	}
+	if err := prepareSchema(migrator, cfg.MigrationsConfig); err != nil {
+		slog.Error("refusing to serve", "error", err)
+		os.Exit(1)
+	}
+
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+	runWorker := func(run func(ctx context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workersCtx)
+		}()
+	}
+
+	purger := service.NewPurger(subscriptionRepo, cfg.PurgeConfig.Retention, cfg.PurgeConfig.Interval)
+	runWorker(purger.Run)
+
+	outboxPublisher, closePublisher, err := newOutboxPublisher(cfg.OutboxConfig)
+	if err != nil {
+		slog.Error("failed to create outbox publisher", "error", err)
+		os.Exit(1)
+	}
+	defer closePublisher()
+
+	outboxRelay := service.NewOutboxRelay(subscriptionRepo, outboxPublisher, cfg.OutboxConfig.Interval,
+		cfg.OutboxConfig.BatchSize, cfg.OutboxConfig.Lease, cfg.OutboxConfig.MaxBackoff)
+	runWorker(outboxRelay.Run)
+
+	webhookDispatcher := service.NewWebhookDispatcher(subscriptionRepo, webhook.NewClient(cfg.WebhookConfig.Timeout),
+		cfg.WebhookConfig.Interval, cfg.WebhookConfig.BatchSize, cfg.WebhookConfig.Lease,
+		cfg.WebhookConfig.MaxAttempts, cfg.WebhookConfig.MaxBackoff)
+	runWorker(webhookDispatcher.Run)
 
-	subscriptionService := service.NewSubscription(subscriptionRepo)
+	reminderChannels, err := newReminderChannels(cfg.ReminderConfig, subscriptionRepo)
+	if err != nil {
+		slog.Error("failed to create reminder notifiers", "error", err)
+		os.Exit(1)
+	}
+	reminderScheduler := service.NewReminderScheduler(subscriptionRepo, reminderChannels,
+		cfg.ReminderConfig.Interval, cfg.ReminderConfig.WindowDays)
+	runWorker(reminderScheduler.Run)
+
+	if cfg.MetricsConfig.Enabled {
+		if err := metrics.RegisterDBStats(subscriptionRepo.Pool(), "postgres"); err != nil {
+			slog.Error("failed to register database metrics", "error", err)
+			os.Exit(1)
+		}
+		statsCollector := service.NewStatsCollector(subscriptionRepo, cfg.MetricsConfig.Interval)
+		runWorker(statsCollector.Run)
+	}
+
+	budgetEvaluator := service.NewBudgetEvaluator(subscriptionRepo, subscriptionRepo, cfg.BudgetConfig.Interval)
+	runWorker(budgetEvaluator.Run)
+
+	subscriptionService := service.NewSubscription(subscriptionRepo, subscriptionRepo)
 	subscriptionHandlers := http.NewSubscriptionHandler(subscriptionService)
 
+	graphqlHandlers, err := graphqlApi.NewHandler(subscriptionService, graphqlApi.Limits{
+		MaxDepth:              cfg.GraphQLConfig.MaxDepth,
+		MaxComplexity:         cfg.GraphQLConfig.MaxComplexity,
+		MaxIntrospectionDepth: cfg.GraphQLConfig.MaxIntrospectionDepth,
+	})
+	if err != nil {
+		slog.Error("failed to build graphql schema", "error", err)
+		os.Exit(1)
+	}
+
+	batchService := service.NewBatch(subscriptionRepo)
+	batchHandlers := http.NewBatchHandler(batchService)
+
+	statementService := service.NewStatement(subscriptionRepo, subscriptionRepo, subscriptionRepo)
+	statementHandlers := http.NewStatementHandler(statementService)
+
+	calendarService := service.NewCalendar(subscriptionRepo, subscriptionRepo)
+	calendarHandlers := http.NewCalendarHandler(calendarService)
+
+	healthService := service.NewHealth(subscriptionRepo, cfg.HealthConfig.CheckTimeout, migrator.Latest())
+	healthHandlers := http.NewHealthHandler(healthService)
+
+	auditService := service.NewAudit(subscriptionRepo)
+	auditHandlers := http.NewAuditHandler(auditService)
+
+	webhookService := service.NewWebhook(subscriptionRepo)
+	webhookHandlers := http.NewWebhookHandler(webhookService)
+
+	budgetService := service.NewBudget(subscriptionRepo, subscriptionRepo)
+	budgetHandlers := http.NewBudgetHandler(budgetService)
+
 	r := chi.NewRouter()
+	r.Use(pkgHttp.RequestIDMiddleware)
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		r.Use(pkgHttp.CORSMiddleware(pkgHttp.CORSConfig{
+			AllowedOrigins:   cfg.CORS.AllowedOrigins,
+			AllowedMethods:   cfg.CORS.AllowedMethods,
+			AllowedHeaders:   cfg.CORS.AllowedHeaders,
+			ExposedHeaders:   cfg.CORS.ExposedHeaders,
+			AllowCredentials: cfg.CORS.AllowCredentials,
+			MaxAge:           cfg.CORS.MaxAge,
+		}))
+	}
+	r.Use(pkgHttp.BodyLimitMiddleware(cfg.HTTPConfig.MaxBodyBytes))
+	r.Use(pkgHttp.ActorMiddleware)
+	r.Use(pkgHttp.TracingMiddleware)
 	r.Use(pkgHttp.LoggingMiddleware)
+	if cfg.MetricsConfig.Enabled {
+		r.Use(pkgHttp.MetricsMiddleware)
+	}
+	// Rejected requests are traced, logged and counted like any other.
+	if cfg.RateLimitConfig.Enabled {
+		routeLimits := make(map[string]ratelimit.Limit, len(cfg.RateLimitConfig.Routes))
+		for route, limit := range cfg.RateLimitConfig.Routes {
+			routeLimits[route] = rateLimit(limit)
+		}
+		trustedProxies, err := cfg.RateLimitConfig.TrustedProxyPrefixes()
+		if err != nil {
+			slog.Error("invalid trusted proxies", "error", err)
+			os.Exit(1)
+		}
+		limiter := pkgHttp.NewRateLimiter(ratelimit.NewMemoryStore(), r, pkgHttp.RateLimitConfig{
+			Default:        rateLimit(cfg.RateLimitConfig.Default),
+			Routes:         routeLimits,
+			Address:        rateLimit(cfg.RateLimitConfig.AddressLimit),
+			TrustedProxies: trustedProxies,
+		})
+		r.Use(limiter.Middleware)
+	}
+	// Recovery runs inside logging and metrics, so a panic is logged and counted as the 500 it
+	// turns into.
+	r.Use(pkgHttp.RecoveryMiddleware)
+	if cfg.MetricsConfig.Enabled {
+		r.Handle(cfg.MetricsConfig.Path, metrics.Handler())
+	}
 	r.Get("/swagger/*", httpSwagger.WrapHandler)
+	healthHandlers.WithHealthHandlers(r)
 	subscriptionHandlers.WithSubscriptionHandlers(r)
+	batchHandlers.WithBatchHandlers(r)
+	auditHandlers.WithAuditHandlers(r)
+	webhookHandlers.WithWebhookHandlers(r)
+	budgetHandlers.WithBudgetHandlers(r)
+	statementHandlers.WithStatementHandlers(r)
+	calendarHandlers.WithCalendarHandlers(r)
+	graphqlHandlers.WithGraphQLHandlers(r)
 
-	server := pkgHttp.CreateServer(r, cfg.Address)
+	server := pkgHttp.CreateServer(r, pkgHttp.ServerConfig{
+		Addr:              cfg.Address,
+		ReadHeaderTimeout: cfg.HTTPConfig.ReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTPConfig.ReadTimeout,
+		WriteTimeout:      cfg.HTTPConfig.WriteTimeout,
+		IdleTimeout:       cfg.HTTPConfig.IdleTimeout,
+		MaxHeaderBytes:    cfg.HTTPConfig.MaxHeaderBytes,
+	})
 	go func() {
-		slog.Info("starting HTTP server", "address", cfg.Address)
-		if err := server.ListenAndServe(); err != nil {
+		var err error
+		if cfg.TLS.CertFile != "" && cfg.TLS.KeyFile != "" {
+			slog.Info("starting HTTPS server", "address", cfg.Address)
+			err = server.ListenAndServeTLS(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		} else {
+			slog.Info("starting HTTP server", "address", cfg.Address)
+			err = server.ListenAndServe()
+		}
+		if err != nil {
 			slog.Error("failed to start server", "error", err)
 		}
 	}()
 
+	grpcServer := grpcApi.NewServer(subscriptionService)
+	if cfg.GRPCConfig.Enabled {
+		listener, err := net.Listen("tcp", cfg.GRPCConfig.Address)
+		if err != nil {
+			slog.Error("failed to listen for gRPC", "address", cfg.GRPCConfig.Address, "error", err)
+			os.Exit(1)
+		}
+		go func() {
+			slog.Info("starting gRPC server", "address", cfg.GRPCConfig.Address)
+			if err := grpcServer.Serve(listener); err != nil {
+				slog.Error("failed to start gRPC server", "error", err)
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
 	slog.Info("waiting for shutdown signal...")
 	<-quit
+	healthService.StartDraining()
+	slog.Info("draining before shutdown", "delay", cfg.HealthConfig.DrainDelay)
+	time.Sleep(cfg.HealthConfig.DrainDelay)
 	slog.Info("shutting down server...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -97,4 +310,102 @@ func main() {
 	} else {
 		slog.Info("server exited gracefully")
 	}
+	stopGRPCServer(ctx, grpcServer)
+
+	stopWorkers()
+	workers.Wait()
+	slog.Info("background workers stopped")
+
+	if err := shutdownTracing(ctx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+}
+
+// stopGRPCServer waits for in-flight calls until ctx expires and then closes the remaining
+// connections.
+func stopGRPCServer(ctx context.Context, server *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		slog.Info("gRPC server exited gracefully")
+	case <-ctx.Done():
+		server.Stop()
+		slog.Error("gRPC server forced to shutdown", "error", ctx.Err())
+	}
+}
+
+func postgresConfig(cfg appConfig.DBConfig) postgres_storage.Config {
+	return postgres_storage.Config{
+		DSN:               cfg.DSN,
+		Host:              cfg.Host,
+		Port:              cfg.Port,
+		Name:              cfg.Name,
+		User:              cfg.User,
+		Password:          cfg.Password,
+		SSLMode:           cfg.SSLMode,
+		MaxOpenConns:      cfg.MaxOpenConns,
+		MaxIdleConns:      cfg.MaxIdleConns,
+		ConnMaxLifetime:   cfg.ConnMaxLifetime,
+		ConnMaxIdleTime:   cfg.ConnMaxIdleTime,
+		StatementTimeout:  cfg.StatementTimeout,
+		ConnectTimeout:    cfg.ConnectTimeout,
+		ConnectBackoff:    cfg.ConnectBackoff,
+		ConnectMaxBackoff: cfg.ConnectMaxBackoff,
+		ReadRetries:       cfg.ReadRetries,
+		ReadRetryBackoff:  cfg.ReadRetryBackoff,
+	}
+}
+
+func rateLimit(cfg appConfig.LimitConfig) ratelimit.Limit {
+	return ratelimit.Limit{Requests: cfg.Requests, Period: cfg.Period, Burst: cfg.Burst}
+}
+
+func newOutboxPublisher(cfg appConfig.OutboxConfig) (publisher.Publisher, func(), error) {
+	switch cfg.Publisher {
+	case "", "stdout":
+		return publisher.NewStdoutPublisher(), func() {}, nil
+	case "file":
+		if cfg.FilePath == "" {
+			return nil, nil, fmt.Errorf("outbox.file_path is required for the file publisher")
+		}
+		pub, f, err := publisher.NewFilePublisher(cfg.FilePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		return pub, func() { _ = f.Close() }, nil
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			return nil, nil, fmt.Errorf("outbox.webhook_url is required for the webhook publisher")
+		}
+		return publisher.NewWebhookPublisher(cfg.WebhookURL, cfg.WebhookTimeout), func() {}, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown outbox publisher %q", cfg.Publisher)
+	}
+}
+
+func newReminderChannels(cfg appConfig.ReminderConfig, webhookRepo *postgres_storage.SubcriptionDB) ([]notifier.Channel, error) {
+	var channels []notifier.Channel
+	for _, name := range cfg.Notifiers {
+		var n notifier.Notifier
+		switch name {
+		case "log":
+			n = notifier.NewLogNotifier()
+		case "webhook":
+			n = service.NewWebhookNotifier(webhookRepo)
+		case "smtp":
+			if cfg.SMTP.Host == "" || cfg.SMTP.From == "" || len(cfg.SMTP.To) == 0 {
+				return nil, fmt.Errorf("reminders.smtp host, from and to are required for the smtp notifier")
+			}
+			n = notifier.NewSMTPNotifier(cfg.SMTP.Host, cfg.SMTP.Port,
+				cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From, cfg.SMTP.To, cfg.SMTP.Timeout)
+		default:
+			return nil, fmt.Errorf("unknown reminder notifier %q", name)
+		}
+		channels = append(channels, notifier.Channel{Name: name, Notifier: n})
+	}
+	return channels, nil
 }
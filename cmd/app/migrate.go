@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	appConfig "github.com/kasparovgs/subscription-aggregation-service/cmd/app/config"
+	"github.com/kasparovgs/subscription-aggregation-service/migrations"
+	"github.com/kasparovgs/subscription-aggregation-service/repository/postgres_storage"
+)
+
+const migrateUsage = "usage: app [--config=PATH] migrate up|down [N]|status|version"
+
+// runCommand runs the subcommand given after the flags instead of the server.
+func runCommand(cfg appConfig.AppConfig, args []string) error {
+	switch args[0] {
+	case "migrate":
+		return runMigrate(cfg, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args[0], migrateUsage)
+	}
+}
+
+func runMigrate(cfg appConfig.AppConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%s", migrateUsage)
+	}
+
+	steps := 1
+	switch {
+	case args[0] != "up" && args[0] != "down" && args[0] != "status" && args[0] != "version":
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], migrateUsage)
+	case args[0] == "down" && len(args) == 2:
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid number of migrations to revert %q\n%s", args[1], migrateUsage)
+		}
+		steps = n
+	case len(args) > 1:
+		return fmt.Errorf("%s", migrateUsage)
+	}
+
+	repo, err := postgres_storage.NewSubscriptionDB(postgresConfig(cfg.DBConfig))
+	if err != nil {
+		return fmt.Errorf("no connection with postgres: %w", err)
+	}
+	defer repo.Close()
+
+	migrator, err := postgres_storage.NewMigrator(repo.Pool(), migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.MigrationsConfig.Timeout)
+	defer cancel()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("applied %d migration(s)\n", applied)
+	case "down":
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("reverted %d migration(s)\n", reverted)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, state)
+		}
+		return w.Flush()
+	case "version":
+		version, dirty, err := migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			fmt.Printf("%d (dirty)\n", version)
+		} else {
+			fmt.Println(version)
+		}
+	}
+	return nil
+}
+
+// prepareSchema applies pending migrations when enabled and makes sure the schema is at
+// least as new as the binary expects. A newer schema is left to a newer binary rolling out.
+func prepareSchema(migrator *postgres_storage.Migrator, cfg appConfig.MigrationsConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+
+	if cfg.Auto {
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("auto-migrate: %w", err)
+		}
+		slog.Info("schema migrated", "applied", applied)
+	}
+
+	version, dirty, err := migrator.Version(ctx)
+	switch {
+	case err != nil:
+		return fmt.Errorf("read schema version: %w", err)
+	case dirty:
+		return fmt.Errorf("schema is dirty at version %d", version)
+	case version < migrator.Latest():
+		return fmt.Errorf("schema version %d is behind %d: run `app migrate up` or enable migrations.auto",
+			version, migrator.Latest())
+	case version > migrator.Latest():
+		slog.Warn("schema is newer than this binary", "schema_version", version, "latest_known", migrator.Latest())
+	default:
+		slog.Info("schema is up to date", "schema_version", version)
+	}
+	return nil
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/client"
+)
+
+var subscriptionHeaders = []string{"ID", "SERVICE", "PRICE", "USER", "START", "END", "PERIOD"}
+
+// filterFlags are the filters shared by list, total and export.
+type filterFlags struct {
+	userID         string
+	serviceName    string
+	startDate      string
+	endDate        string
+	includeDeleted bool
+}
+
+func (f *filterFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.userID, "user", "", "user ID (UUID)")
+	fs.StringVar(&f.serviceName, "service", "", "service name")
+	fs.StringVar(&f.startDate, "start", "", "start date, MM-YYYY")
+	fs.StringVar(&f.endDate, "end", "", "end date, MM-YYYY")
+	fs.BoolVar(&f.includeDeleted, "include-deleted", false, "include deleted subscriptions")
+}
+
+func runCreate(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("create", "")
+	service := fs.String("service", "", "service name (required)")
+	price := fs.Int("price", 0, "price in rubles per billing period (required)")
+	userID := fs.String("user", "", "user ID, UUID (required)")
+	start := fs.String("start", "", "start date, MM-YYYY (required)")
+	end := fs.String("end", "", "end date, MM-YYYY")
+	period := fs.String("billing-period", "", "monthly (default) or yearly")
+	if err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	ctx, cancel := a.withTimeout(ctx)
+	defer cancel()
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	resp, err := c.CreateSubscription(ctx, client.PostCreateSubscriptionRequest{
+		ServiceName:   client.Ptr(*service),
+		Price:         client.Ptr(*price),
+		UserID:        client.Ptr(*userID),
+		StartDate:     client.Ptr(*start),
+		EndDate:       optional(*end),
+		BillingPeriod: optional(*period),
+	})
+	if err != nil {
+		return err
+	}
+	return a.render(resp, []string{"SUBSCRIPTION_ID"}, [][]string{{resp.SubscriptionID}})
+}
+
+func runGet(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("get", "ID")
+	if err := a.parse(fs, args, 1); err != nil {
+		return err
+	}
+	ctx, cancel := a.withTimeout(ctx)
+	defer cancel()
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	resp, err := c.GetSubscription(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.renderSubscription(resp)
+}
+
+func runList(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("list", "")
+	var filter filterFlags
+	filter.register(fs)
+	if err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	ctx, cancel := a.withTimeout(ctx)
+	defer cancel()
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	resp, err := c.ListSubscriptions(ctx, &client.ListSubscriptionsParams{
+		UserID:         optional(filter.userID),
+		ServiceName:    optional(filter.serviceName),
+		StartDate:      optional(filter.startDate),
+		EndDate:        optional(filter.endDate),
+		IncludeDeleted: optionalBool(filter.includeDeleted),
+	})
+	if err != nil {
+		return err
+	}
+
+	headers := subscriptionHeaders
+	if filter.includeDeleted {
+		headers = append(headers[:len(headers):len(headers)], "DELETED")
+	}
+	rows := make([][]string, 0, len(resp.Subscriptions))
+	for _, s := range resp.Subscriptions {
+		row := []string{s.SubscriptionID, s.ServiceName, strconv.Itoa(s.Price), s.UserID,
+			month(s.StartDate), month(s.EndDate), s.BillingPeriod}
+		if filter.includeDeleted {
+			row = append(row, s.DeletedAt)
+		}
+		rows = append(rows, row)
+	}
+	return a.render(resp.Subscriptions, headers, rows)
+}
+
+func runPatch(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("patch", "ID")
+	service := fs.String("service", "", "service name")
+	price := fs.Int("price", 0, "price in rubles per billing period")
+	end := fs.String("end", "", "end date, MM-YYYY")
+	period := fs.String("billing-period", "", "monthly or yearly")
+	if err := a.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	// Only the flags that were given are sent, so the other fields keep their values.
+	var req client.PatchSubscriptionByIDRequest
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "service":
+			req.ServiceName = service
+		case "price":
+			req.Price = price
+		case "end":
+			req.EndDate = end
+		case "billing-period":
+			req.BillingPeriod = period
+		}
+	})
+	if req == (client.PatchSubscriptionByIDRequest{}) {
+		return errors.New("nothing to change: set at least one of --service, --price, --end, --billing-period")
+	}
+	ctx, cancel := a.withTimeout(ctx)
+	defer cancel()
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	resp, err := c.PatchSubscription(ctx, fs.Arg(0), req)
+	if err != nil {
+		return err
+	}
+	return a.renderSubscription(resp)
+}
+
+func runDelete(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("delete", "ID")
+	if err := a.parse(fs, args, 1); err != nil {
+		return err
+	}
+	ctx, cancel := a.withTimeout(ctx)
+	defer cancel()
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	resp, err := c.DeleteSubscription(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return a.renderSubscription(resp)
+}
+
+func runTotal(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("total", "")
+	var filter filterFlags
+	filter.register(fs)
+	if err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if filter.startDate == "" || filter.endDate == "" {
+		return errors.New("--start and --end are required")
+	}
+	ctx, cancel := a.withTimeout(ctx)
+	defer cancel()
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	resp, err := c.GetTotalCost(ctx, &client.GetTotalCostParams{
+		StartDate:      optional(filter.startDate),
+		EndDate:        optional(filter.endDate),
+		UserID:         optional(filter.userID),
+		ServiceName:    optional(filter.serviceName),
+		IncludeDeleted: optionalBool(filter.includeDeleted),
+	})
+	if err != nil {
+		return err
+	}
+	return a.render(resp, []string{"TOTAL_COST"}, [][]string{{strconv.Itoa(resp.TotalCost)}})
+}
+
+func runImport(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("import", "FILE|-")
+	dryRun := fs.Bool("dry-run", false, "only validate the rows")
+	format := fs.String("format", "", "csv or json, by default from the file extension")
+	if err := a.parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	name := fs.Arg(0)
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	}
+	var contentType string
+	switch *format {
+	case "csv":
+		contentType = "text/csv"
+	case "json":
+		contentType = "application/json"
+	default:
+		return fmt.Errorf("unknown import format %q: set --format csv or json", *format)
+	}
+
+	var in io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	ctx, cancel := a.withTimeout(ctx)
+	defer cancel()
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	resp, err := c.ImportSubscriptionsWithBody(ctx, &client.ImportSubscriptionsParams{DryRun: optionalBool(*dryRun)},
+		contentType, in)
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusUnprocessableEntity {
+		var rejected client.PostImportSubscriptionsResponse
+		if apiErr.Decode(&rejected) == nil && len(rejected.Errors) > 0 {
+			for _, e := range rejected.Errors {
+				fmt.Fprintf(a.stderr, "row %d: %s\n", e.Row, e.Error)
+			}
+			return fmt.Errorf("%d of %d rows are invalid, nothing was imported", len(rejected.Errors), rejected.Total)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return a.render(resp, []string{"TOTAL", "IMPORTED", "DRY_RUN"},
+		[][]string{{strconv.Itoa(resp.Total), strconv.Itoa(resp.Imported), strconv.FormatBool(resp.DryRun)}})
+}
+
+func runExport(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("export", "")
+	var filter filterFlags
+	filter.register(fs)
+	format := fs.String("format", "csv", "csv or jsonl")
+	out := fs.String("out", "", "file to write, stdout by default")
+	if err := a.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "jsonl" {
+		return fmt.Errorf("unknown export format %q: use csv or jsonl", *format)
+	}
+
+	c, err := a.client()
+	if err != nil {
+		return err
+	}
+	body, err := c.ExportSubscriptions(ctx, &client.ExportSubscriptionsParams{
+		Format:         format,
+		UserID:         optional(filter.userID),
+		ServiceName:    optional(filter.serviceName),
+		StartDate:      optional(filter.startDate),
+		EndDate:        optional(filter.endDate),
+		IncludeDeleted: optionalBool(filter.includeDeleted),
+	})
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if *out == "" {
+		_, err = io.Copy(a.stdout, body)
+		return err
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		os.Remove(*out)
+		return err
+	}
+	return f.Close()
+}
+
+func (a *app) renderSubscription(s *client.GetSubscriptionByIDResponse) error {
+	return a.render(s, subscriptionHeaders, [][]string{{s.SubscriptionID, s.ServiceName, strconv.Itoa(s.Price),
+		s.UserID, month(s.StartDate), month(s.EndDate), s.BillingPeriod}})
+}
+
+// optional leaves empty flags out of the request.
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func optionalBool(value bool) *bool {
+	if !value {
+		return nil
+	}
+	return &value
+}
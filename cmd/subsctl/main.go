@@ -0,0 +1,231 @@
+// Command subsctl manages subscriptions through the HTTP API of the service.
+//
+//	subsctl [global flags] <command> [flags] [args]
+//
+// The API address and credentials come from a profile in the config file, see
+// subsctl profile help.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/client"
+)
+
+// errUsage makes the command exit with status 2 after its usage was printed.
+var errUsage = errors.New("usage")
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, app *app, args []string) error
+}
+
+var commands = map[string]command{
+	"create":  {usage: "create a subscription", run: runCreate},
+	"get":     {usage: "show a subscription", run: runGet},
+	"list":    {usage: "list subscriptions matching filters", run: runList},
+	"patch":   {usage: "change fields of a subscription", run: runPatch},
+	"delete":  {usage: "delete a subscription", run: runDelete},
+	"total":   {usage: "total cost of subscriptions in a period", run: runTotal},
+	"import":  {usage: "import subscriptions from a CSV or JSON file", run: runImport},
+	"export":  {usage: "export subscriptions as CSV or JSON Lines", run: runExport},
+	"profile": {usage: "manage profiles with the API address and credentials", run: runProfile},
+}
+
+// globalFlags may be given before or after the command.
+type globalFlags struct {
+	configPath string
+	profile    string
+	baseURL    string
+	apiKey     string
+	actor      string
+	output     string
+	timeout    time.Duration
+}
+
+func (g *globalFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.configPath, "config", g.configPath, "profiles file (env SUBSCTL_CONFIG)")
+	fs.StringVar(&g.profile, "profile", g.profile, "profile to use (env SUBSCTL_PROFILE)")
+	fs.StringVar(&g.baseURL, "base-url", g.baseURL, "API address, overrides the profile (env SUBSCTL_BASE_URL)")
+	fs.StringVar(&g.apiKey, "api-key", g.apiKey, "API key, overrides the profile (env SUBSCTL_API_KEY)")
+	fs.StringVar(&g.actor, "actor", g.actor, "actor recorded in the audit log, overrides the profile (env SUBSCTL_ACTOR)")
+	fs.StringVar(&g.output, "o", g.output, "output format: table, json or csv")
+	fs.DurationVar(&g.timeout, "timeout", g.timeout, "request timeout")
+}
+
+// app is what the commands share: the parsed global flags, the profiles and the output.
+type app struct {
+	flags  globalFlags
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	a := &app{
+		flags: globalFlags{
+			configPath: os.Getenv("SUBSCTL_CONFIG"),
+			profile:    os.Getenv("SUBSCTL_PROFILE"),
+			baseURL:    os.Getenv("SUBSCTL_BASE_URL"),
+			apiKey:     os.Getenv("SUBSCTL_API_KEY"),
+			actor:      os.Getenv("SUBSCTL_ACTOR"),
+			output:     outputTable,
+			timeout:    30 * time.Second,
+		},
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+
+	fs := flag.NewFlagSet("subsctl", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	a.flags.register(fs)
+	fs.Usage = func() { a.usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if fs.NArg() == 0 || fs.Arg(0) == "help" {
+		a.usage(fs)
+		return 2
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		fmt.Fprintf(a.stderr, "subsctl: unknown command %q\n", fs.Arg(0))
+		a.usage(fs)
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := cmd.run(ctx, a, fs.Args()[1:]); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+		fmt.Fprintf(a.stderr, "subsctl: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func (a *app) usage(fs *flag.FlagSet) {
+	fmt.Fprintln(a.stderr, "Usage: subsctl [global flags] <command> [flags] [args]")
+	fmt.Fprintln(a.stderr, "\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(a.stderr, "  %-8s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(a.stderr, "\nGlobal flags:")
+	fs.PrintDefaults()
+	fmt.Fprintln(a.stderr, "\nRun subsctl <command> -h for the flags of a command.")
+}
+
+// flagSet returns the flag set of a command, which also accepts the global flags.
+func (a *app) flagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet("subsctl "+name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: subsctl %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	a.flags.register(fs)
+	return fs
+}
+
+// parse parses the command flags and checks the number of positional arguments.
+func (a *app) parse(fs *flag.FlagSet, args []string, nargs int) error {
+	if err := parseInterspersed(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != nargs {
+		fs.Usage()
+		return errUsage
+	}
+	switch a.flags.output {
+	case outputTable, outputJSON, outputCSV:
+	default:
+		return fmt.Errorf("unknown output format %q: use table, json or csv", a.flags.output)
+	}
+	return nil
+}
+
+// parseInterspersed lets flags follow the positional arguments, as in subsctl patch ID --price 500,
+// which flag.Parse alone stops parsing at ID.
+func parseInterspersed(fs *flag.FlagSet, args []string) error {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		if consumed := args[:len(args)-fs.NArg()]; len(consumed) > 0 && consumed[len(consumed)-1] == "--" {
+			positional = append(positional, fs.Args()...)
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	// Leaves the positional arguments in fs.Args.
+	return fs.Parse(append([]string{"--"}, positional...))
+}
+
+// client creates the API client from the selected profile and the overrides.
+func (a *app) client() (*client.Client, error) {
+	cfg, err := loadConfig(a.flags.configPath)
+	if err != nil {
+		return nil, err
+	}
+	name, profile := cfg.selected(a.flags.profile)
+	if a.flags.profile != "" && name != a.flags.profile {
+		return nil, fmt.Errorf("profile %q does not exist", a.flags.profile)
+	}
+
+	baseURL := firstNonEmpty(a.flags.baseURL, profile.BaseURL, defaultBaseURL)
+	// Requests are bounded by --timeout through their context instead, so that exports can
+	// stream for as long as they need.
+	return client.New(baseURL,
+		client.WithHTTPClient(&http.Client{}),
+		client.WithAPIKey(firstNonEmpty(a.flags.apiKey, profile.APIKey)),
+		client.WithActor(firstNonEmpty(a.flags.actor, profile.Actor)),
+	)
+}
+
+// withTimeout bounds a request by --timeout. Exports are not bounded.
+func (a *app) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if a.flags.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, a.flags.timeout)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
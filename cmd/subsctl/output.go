@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+)
+
+// render prints the result in the --o format: value as indented JSON, or the rows under
+// headers as an aligned table or CSV.
+func (a *app) render(value any, headers []string, rows [][]string) error {
+	switch a.flags.output {
+	case outputJSON:
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(value)
+	case outputCSV:
+		w := csv.NewWriter(a.stdout)
+		_ = w.Write(headers)
+		_ = w.WriteAll(rows)
+		return w.Error()
+	default:
+		w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, strings.Join(headers, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	}
+}
+
+// month shows the timestamps of the API in the MM-YYYY form the commands accept.
+func month(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.Format("01-2006")
+}
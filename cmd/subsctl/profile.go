@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultBaseURL = "http://localhost:8080"
+	defaultProfile = "default"
+)
+
+// config is the profiles file:
+//
+//	current_profile: local
+//	profiles:
+//	  local:
+//	    base_url: http://localhost:8080
+//	    api_key: secret
+//	    actor: alice
+type config struct {
+	CurrentProfile string              `yaml:"current_profile,omitempty"`
+	Profiles       map[string]*profile `yaml:"profiles,omitempty"`
+
+	path string
+}
+
+type profile struct {
+	BaseURL string `yaml:"base_url,omitempty" json:"base_url,omitempty"`
+	APIKey  string `yaml:"api_key,omitempty" json:"api_key,omitempty"`
+	Actor   string `yaml:"actor,omitempty" json:"actor,omitempty"`
+}
+
+// configPath returns path or, when empty, subsctl/config.yml in the user config directory.
+func configPath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locate the profiles file: %w; set --config", err)
+	}
+	return filepath.Join(dir, "subsctl", "config.yml"), nil
+}
+
+// loadConfig reads the profiles file; a missing file is an empty config.
+func loadConfig(path string) (*config, error) {
+	path, err := configPath(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &config{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// save writes the file readable by the owner only, as it holds API keys.
+func (c *config) save() error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0o600)
+}
+
+// selected returns the profile named by name, else the current one, else the default one.
+// A missing profile is returned empty.
+func (c *config) selected(name string) (string, profile) {
+	name = firstNonEmpty(name, c.CurrentProfile, defaultProfile)
+	if p, ok := c.Profiles[name]; ok && p != nil {
+		return name, *p
+	}
+	if name == defaultProfile || name == c.CurrentProfile {
+		return name, profile{}
+	}
+	return "", profile{}
+}
+
+func (c *config) names() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+const profileUsage = `Usage:
+  subsctl profile list
+  subsctl profile show [NAME]
+  subsctl profile set NAME [--base-url URL] [--api-key KEY] [--actor ACTOR]
+  subsctl profile use NAME
+  subsctl profile delete NAME`
+
+func runProfile(_ context.Context, a *app, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" {
+		fmt.Fprintln(a.stderr, profileUsage)
+		return errUsage
+	}
+
+	switch args[0] {
+	case "list":
+		return profileList(a, args[1:])
+	case "show":
+		return profileShow(a, args[1:])
+	case "set":
+		return profileSet(a, args[1:])
+	case "use":
+		return profileUse(a, args[1:])
+	case "delete":
+		return profileDelete(a, args[1:])
+	default:
+		fmt.Fprintf(a.stderr, "subsctl: unknown profile command %q\n%s\n", args[0], profileUsage)
+		return errUsage
+	}
+}
+
+func profileList(a *app, args []string) error {
+	set := a.flagSet("profile list", "")
+	if err := a.parse(set, args, 0); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(a.flags.configPath)
+	if err != nil {
+		return err
+	}
+	current, _ := cfg.selected("")
+	profiles := make(map[string]profile, len(cfg.Profiles))
+	rows := make([][]string, 0, len(cfg.Profiles))
+	for _, name := range cfg.names() {
+		p := *cfg.Profiles[name]
+		p.APIKey = redact(p.APIKey)
+		profiles[name] = p
+		marker := ""
+		if name == current {
+			marker = "*"
+		}
+		rows = append(rows, []string{marker, name, p.BaseURL, p.Actor, p.APIKey})
+	}
+	return a.render(profiles, []string{"CURRENT", "NAME", "BASE_URL", "ACTOR", "API_KEY"}, rows)
+}
+
+func profileShow(a *app, args []string) error {
+	set := a.flagSet("profile show", "[NAME]")
+	if err := parseInterspersed(set, args); err != nil {
+		return err
+	}
+	if set.NArg() > 1 {
+		set.Usage()
+		return errUsage
+	}
+	cfg, err := loadConfig(a.flags.configPath)
+	if err != nil {
+		return err
+	}
+	name, p := cfg.selected(firstNonEmpty(set.Arg(0), a.flags.profile))
+	if name == "" {
+		return fmt.Errorf("profile %q does not exist", firstNonEmpty(set.Arg(0), a.flags.profile))
+	}
+	p.APIKey = redact(p.APIKey)
+	return a.render(p, []string{"NAME", "BASE_URL", "ACTOR", "API_KEY"},
+		[][]string{{name, firstNonEmpty(p.BaseURL, defaultBaseURL), p.Actor, p.APIKey}})
+}
+
+func profileSet(a *app, args []string) error {
+	set := a.flagSet("profile set", "NAME")
+	// The profile fields are set through the same flags that override them elsewhere.
+	if err := a.parse(set, args, 1); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(a.flags.configPath)
+	if err != nil {
+		return err
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*profile{}
+	}
+	name := set.Arg(0)
+	p, ok := cfg.Profiles[name]
+	if !ok || p == nil {
+		p = &profile{}
+		cfg.Profiles[name] = p
+	}
+	set.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "base-url":
+			p.BaseURL = a.flags.baseURL
+		case "api-key":
+			p.APIKey = a.flags.apiKey
+		case "actor":
+			p.Actor = a.flags.actor
+		}
+	})
+	if cfg.CurrentProfile == "" {
+		cfg.CurrentProfile = name
+	}
+	if err := cfg.save(); err != nil {
+		return err
+	}
+	fmt.Fprintf(a.stderr, "profile %q saved to %s\n", name, cfg.path)
+	return nil
+}
+
+func profileUse(a *app, args []string) error {
+	set := a.flagSet("profile use", "NAME")
+	if err := a.parse(set, args, 1); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(a.flags.configPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Profiles[set.Arg(0)]; !ok {
+		return fmt.Errorf("profile %q does not exist", set.Arg(0))
+	}
+	cfg.CurrentProfile = set.Arg(0)
+	return cfg.save()
+}
+
+func profileDelete(a *app, args []string) error {
+	set := a.flagSet("profile delete", "NAME")
+	if err := a.parse(set, args, 1); err != nil {
+		return err
+	}
+	cfg, err := loadConfig(a.flags.configPath)
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Profiles[set.Arg(0)]; !ok {
+		return fmt.Errorf("profile %q does not exist", set.Arg(0))
+	}
+	delete(cfg.Profiles, set.Arg(0))
+	if cfg.CurrentProfile == set.Arg(0) {
+		cfg.CurrentProfile = ""
+	}
+	return cfg.save()
+}
+
+// redact keeps the last four characters of a key so profiles can still be told apart.
+func redact(key string) string {
+	if key == "" {
+		return ""
+	}
+	if len(key) <= 8 {
+		return "****"
+	}
+	return "****" + key[len(key)-4:]
+}
@@ -0,0 +1,47 @@
+package domain
+
+const (
+	BatchOpCreate = "create"
+	BatchOpPatch  = "patch"
+	BatchOpDelete = "delete"
+)
+
+const (
+	// BatchModeAtomic applies every operation or none of them.
+	BatchModeAtomic = "atomic"
+	// BatchModeBestEffort commits the operations that succeeded and reports the rest.
+	BatchModeBestEffort = "best_effort"
+)
+
+const (
+	BatchStatusOK         = "ok"
+	BatchStatusFailed     = "failed"
+	BatchStatusRolledBack = "rolled_back"
+	BatchStatusSkipped    = "skipped"
+)
+
+func IsBatchMode(mode string) bool {
+	return mode == BatchModeAtomic || mode == BatchModeBestEffort
+}
+
+// BatchOperation is a single create, patch or delete. For patch and delete Subscription
+// carries the ID; patch only applies the fields that are set, like PATCH /subscriptions/{id}.
+type BatchOperation struct {
+	Op           string
+	Subscription *Subscription
+}
+
+type BatchOperationResult struct {
+	Index        int           `json:"index"`
+	Op           string        `json:"op"`
+	Status       string        `json:"status"`
+	Subscription *Subscription `json:"subscription,omitempty"`
+	Code         int           `json:"code,omitempty"`
+	Error        string        `json:"error,omitempty"`
+}
+
+type BatchResult struct {
+	Mode      string                 `json:"mode"`
+	Committed bool                   `json:"committed"`
+	Results   []BatchOperationResult `json:"results"`
+}
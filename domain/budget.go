@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Budget is a monthly spend limit of a user, for all subscriptions or for a single service.
+type Budget struct {
+	BudgetID     uuid.UUID `json:"budget_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	ServiceName  *string   `json:"service_name"`
+	MonthlyLimit int       `json:"monthly_limit"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// BudgetAlert is recorded once per budget and month when the spend exceeds the limit.
+type BudgetAlert struct {
+	AlertID      uuid.UUID `json:"alert_id"`
+	BudgetID     uuid.UUID `json:"budget_id"`
+	Month        time.Time `json:"month"`
+	MonthlyLimit int       `json:"monthly_limit"`
+	Spent        int       `json:"spent"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type BudgetStatus struct {
+	Budget    Budget       `json:"budget"`
+	Month     time.Time    `json:"month"`
+	Spent     int          `json:"spent"`
+	Remaining int          `json:"remaining"`
+	Exceeded  bool         `json:"exceeded"`
+	Alert     *BudgetAlert `json:"alert,omitempty"`
+}
+
+// MonthStart truncates t to the first day of its month.
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarEvent is an upcoming renewal or end of a subscription. Kind is one of the
+// ReminderKind constants; Amount is only set for renewals.
+type CalendarEvent struct {
+	Subscription Subscription
+	Kind         string
+	Date         time.Time
+	Amount       int
+}
+
+// CalendarToken is the secret that gives access to the calendar feed of a user. Only the
+// SHA-256 hash of the token is stored.
+type CalendarToken struct {
+	UserID    uuid.UUID
+	TokenHash string
+	CreatedAt time.Time
+}
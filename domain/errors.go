@@ -8,6 +8,8 @@ var (
 	CodeUnauthorized = 401
 	CodeAlreadyExist = 409
 	CodeForbidden    = 403
+
+	CodePayloadTooLarge = 413
 )
 
 type MyErr struct {
@@ -42,4 +44,7 @@ var (
 	ErrBadRequest = func(msg string) *MyErr {
 		return NewError(CodeBadRequest, "Bad request: "+msg)
 	}
+	ErrPayloadTooLarge = func(msg string) *MyErr {
+		return NewError(CodePayloadTooLarge, "Payload too large: "+msg)
+	}
 )
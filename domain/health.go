@@ -0,0 +1,22 @@
+package domain
+
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// HealthCheck is the outcome of checking one dependency of the service.
+type HealthCheck struct {
+	Name   string
+	Status string
+	Error  string
+}
+
+// HealthReport tells whether the service can take traffic. SchemaVersion is the applied
+// migration version, 0 when it could not be read.
+type HealthReport struct {
+	Status        string
+	Draining      bool
+	SchemaVersion uint
+	Checks        []HealthCheck
+}
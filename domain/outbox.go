@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventSubscriptionCreated  = "SubscriptionCreated"
+	EventSubscriptionUpdated  = "SubscriptionUpdated"
+	EventSubscriptionDeleted  = "SubscriptionDeleted"
+	EventSubscriptionRestored = "SubscriptionRestored"
+)
+
+// OutboxMessage is a lifecycle event written together with the change that caused it.
+type OutboxMessage struct {
+	MessageID   uuid.UUID       `json:"message_id"`
+	EventType   string          `json:"event_type"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload" swaggertype:"object"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Attempts    int             `json:"attempts"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceChange schedules a new price of a subscription starting with the month of EffectiveFrom.
+type PriceChange struct {
+	PriceChangeID  uuid.UUID `json:"price_change_id"`
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	EffectiveFrom  time.Time `json:"effective_from"`
+	Price          int       `json:"price"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type ForecastFilter struct {
+	UserID      *uuid.UUID  `json:"user_id,omitempty"`
+	ServiceName *string     `json:"service_name,omitempty"`
+	From        time.Time   `json:"from"`
+	Months      int         `json:"months"`
+	Exclude     []uuid.UUID `json:"exclude,omitempty"`
+}
+
+type ForecastItem struct {
+	SubscriptionID uuid.UUID `json:"subscription_id"`
+	ServiceName    string    `json:"service_name"`
+	Amount         int       `json:"amount"`
+}
+
+type ForecastMonth struct {
+	Month time.Time      `json:"month"`
+	Total int            `json:"total"`
+	Items []ForecastItem `json:"items"`
+}
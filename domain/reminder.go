@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+const (
+	ReminderKindExpiry  = "expiry"
+	ReminderKindRenewal = "renewal"
+)
+
+// Reminder tells a user that a subscription ends or renews on DueDate.
+// A reminder is identified by subscription, kind and due date and is sent once.
+type Reminder struct {
+	Subscription Subscription `json:"subscription"`
+	Kind         string       `json:"kind"`
+	DueDate      time.Time    `json:"due_date"`
+}
+
+// NextRenewal returns the first billing date of the subscription that is not before from and
+// comes after the start month: the start of every month for monthly subscriptions and of the
+// start month anniversaries for yearly ones, as ChargeForMonth bills them. ok is false when
+// the subscription ends before it renews.
+func (s *Subscription) NextRenewal(from time.Time) (next time.Time, ok bool) {
+	month := MonthStart(from)
+	if month.Before(from) {
+		month = month.AddDate(0, 1, 0)
+	}
+	if first := MonthStart(s.StartDate).AddDate(0, 1, 0); month.Before(first) {
+		month = first
+	}
+	for range 12 {
+		if s.ChargeForMonth(month, nil) > 0 {
+			return month, true
+		}
+		month = month.AddDate(0, 1, 0)
+	}
+	return time.Time{}, false
+}
+
+// LastActiveDay returns the last day a subscription with the given end date is active:
+// end dates are month precise and the end month is paid in full.
+func LastActiveDay(endDate time.Time) time.Time {
+	y, m, _ := endDate.Date()
+	return time.Date(y, m+1, 0, 0, 0, 0, 0, endDate.Location())
+}
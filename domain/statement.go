@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BankTransaction is a single line of a bank statement. Amount is negative for charges.
+type BankTransaction struct {
+	Date        time.Time
+	Description string
+	Amount      float64
+}
+
+const (
+	CandidateStatusPending   = "pending"
+	CandidateStatusConfirmed = "confirmed"
+	CandidateStatusDismissed = "dismissed"
+)
+
+// SubscriptionCandidate is a recurring charge found in a bank statement that may be a
+// subscription. It becomes one only after the user confirms it.
+type SubscriptionCandidate struct {
+	CandidateID    uuid.UUID  `json:"candidate_id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Merchant       string     `json:"merchant"`
+	ServiceName    string     `json:"service_name"`
+	Price          int        `json:"price"`
+	BillingPeriod  string     `json:"billing_period"`
+	StartDate      time.Time  `json:"start_date"`
+	LastChargedAt  time.Time  `json:"last_charged_at"`
+	Occurrences    int        `json:"occurrences"`
+	Status         string     `json:"status"`
+	SubscriptionID *uuid.UUID `json:"subscription_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,8 @@
+package domain
+
+// SubscriptionStats is a snapshot of the figures exported as business metrics.
+type SubscriptionStats struct {
+	ActiveSubscriptions   int64
+	ActiveUsers           int64
+	PendingOutboxMessages int64
+}
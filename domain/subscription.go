@@ -13,6 +13,45 @@ type Subscription struct {
 	UserID         uuid.UUID  `json:"user_id"`
 	StartDate      time.Time  `json:"start_date"`
 	EndDate        *time.Time `json:"end_date"`
+	BillingPeriod  string     `json:"billing_period"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	// PriceChanges is only filled in the events of price change actions.
+	PriceChanges []PriceChange `json:"price_changes,omitempty"`
+}
+
+const (
+	BillingPeriodMonthly = "monthly"
+	BillingPeriodYearly  = "yearly"
+)
+
+func IsBillingPeriod(period string) bool {
+	return period == BillingPeriodMonthly || period == BillingPeriodYearly
+}
+
+// ChargeForMonth returns what the subscription charges in the given month. Price is per billing
+// period: monthly subscriptions are charged every active month, yearly ones in the start month
+// and its anniversaries. The latest price change effective by the month replaces Price;
+// changes must be sorted by EffectiveFrom.
+func (s *Subscription) ChargeForMonth(month time.Time, changes []PriceChange) int {
+	month = MonthStart(month)
+	if month.Before(MonthStart(s.StartDate)) {
+		return 0
+	}
+	if s.EndDate != nil && month.After(MonthStart(*s.EndDate)) {
+		return 0
+	}
+	if s.BillingPeriod == BillingPeriodYearly && month.Month() != s.StartDate.Month() {
+		return 0
+	}
+
+	price := s.Price
+	for _, c := range changes {
+		if c.EffectiveFrom.After(month) {
+			break
+		}
+		price = c.Price
+	}
+	return price
 }
 
 type SubscriptionFilter struct {
@@ -21,6 +60,8 @@ type SubscriptionFilter struct {
 	Price       *int       `json:"price,omitempty"`
 	StartDate   *time.Time `json:"start_date,omitempty"`
 	EndDate     *time.Time `json:"end_date,omitempty"`
+
+	IncludeDeleted bool `json:"include_deleted,omitempty"`
 }
 
 type TotalCostFilter struct {
@@ -28,4 +69,6 @@ type TotalCostFilter struct {
 	ServiceName *string    `json:"service_name,omitempty"`
 	StartDate   time.Time  `json:"start_time"`
 	EndDate     time.Time  `json:"end_time"`
+
+	IncludeDeleted bool `json:"include_deleted,omitempty"`
 }
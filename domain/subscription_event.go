@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	EventActionCreated  = "created"
+	EventActionUpdated  = "updated"
+	EventActionReplaced = "replaced"
+	EventActionDeleted  = "deleted"
+	EventActionRestored = "restored"
+	// A scheduled price change is recorded with the schedule in the price_changes of the snapshots.
+	EventActionPriceChangeScheduled = "price_change_scheduled"
+	EventActionPriceChangeDeleted   = "price_change_deleted"
+)
+
+type SubscriptionEvent struct {
+	EventID        uuid.UUID       `json:"event_id"`
+	SubscriptionID uuid.UUID       `json:"subscription_id"`
+	Action         string          `json:"action"`
+	Actor          string          `json:"actor"`
+	RequestID      string          `json:"request_id"`
+	OccurredAt     time.Time       `json:"occurred_at"`
+	Before         json.RawMessage `json:"before" swaggertype:"object"`
+	After          json.RawMessage `json:"after" swaggertype:"object"`
+}
+
+type SubscriptionEventFilter struct {
+	SubscriptionID *uuid.UUID `json:"subscription_id,omitempty"`
+	Actor          *string    `json:"actor,omitempty"`
+	Action         *string    `json:"action,omitempty"`
+	From           *time.Time `json:"from,omitempty"`
+	To             *time.Time `json:"to,omitempty"`
+	Limit          int        `json:"limit,omitempty"`
+	Offset         int        `json:"offset,omitempty"`
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSubscriptionChargeForMonth(t *testing.T) {
+	end := month(2025, time.June)
+	changes := []PriceChange{
+		{EffectiveFrom: month(2025, time.April), Price: 899},
+		{EffectiveFrom: month(2025, time.July), Price: 999},
+	}
+	monthly := Subscription{Price: 799, StartDate: month(2025, time.January), BillingPeriod: BillingPeriodMonthly}
+	ending := monthly
+	ending.EndDate = &end
+	yearly := Subscription{Price: 7990, StartDate: time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC),
+		BillingPeriod: BillingPeriodYearly}
+
+	tests := []struct {
+		name    string
+		subs    Subscription
+		month   time.Time
+		changes []PriceChange
+		want    int
+	}{
+		{"before the start", monthly, month(2024, time.December), nil, 0},
+		{"start month", monthly, month(2025, time.January), nil, 799},
+		{"mid-month date", monthly, time.Date(2025, time.February, 20, 13, 0, 0, 0, time.UTC), nil, 799},
+		{"no end date", monthly, month(2030, time.January), nil, 799},
+		{"end month", ending, month(2025, time.June), nil, 799},
+		{"after the end", ending, month(2025, time.July), nil, 0},
+		{"before the first price change", monthly, month(2025, time.March), changes, 799},
+		{"first price change month", monthly, month(2025, time.April), changes, 899},
+		{"between price changes", monthly, month(2025, time.May), changes, 899},
+		{"latest price change", monthly, month(2026, time.January), changes, 999},
+		{"yearly start month", yearly, month(2024, time.March), nil, 7990},
+		{"yearly between anniversaries", yearly, month(2024, time.September), nil, 0},
+		{"yearly anniversary", yearly, month(2025, time.March), nil, 7990},
+		{"yearly anniversary after a price change", yearly, month(2025, time.March),
+			[]PriceChange{{EffectiveFrom: month(2025, time.January), Price: 8990}}, 8990},
+		{"yearly price change before the start", yearly, month(2023, time.March),
+			[]PriceChange{{EffectiveFrom: month(2023, time.January), Price: 8990}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.subs.ChargeForMonth(tt.month, tt.changes); got != tt.want {
+				t.Fatalf("got %d; want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	WebhookEventSubscriptionCreated      = "subscription.created"
+	WebhookEventSubscriptionUpdated      = "subscription.updated"
+	WebhookEventSubscriptionDeleted      = "subscription.deleted"
+	WebhookEventSubscriptionPriceChanged = "subscription.price_changed"
+	WebhookEventSubscriptionEndingSoon   = "subscription.ending_soon"
+)
+
+var WebhookEventTypes = []string{
+	WebhookEventSubscriptionCreated,
+	WebhookEventSubscriptionUpdated,
+	WebhookEventSubscriptionDeleted,
+	WebhookEventSubscriptionPriceChanged,
+	WebhookEventSubscriptionEndingSoon,
+}
+
+func IsWebhookEventType(eventType string) bool {
+	for _, t := range WebhookEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEventsOfChange returns the webhook events caused by a change of a subscription,
+// given its audit action and the state before and after it.
+func WebhookEventsOfChange(action string, before, after *Subscription) []string {
+	switch action {
+	case EventActionCreated:
+		return []string{WebhookEventSubscriptionCreated}
+	case EventActionUpdated, EventActionReplaced:
+		events := []string{WebhookEventSubscriptionUpdated}
+		if before != nil && before.Price != after.Price {
+			events = append(events, WebhookEventSubscriptionPriceChanged)
+		}
+		return events
+	case EventActionPriceChangeScheduled, EventActionPriceChangeDeleted:
+		return []string{WebhookEventSubscriptionUpdated}
+	case EventActionDeleted:
+		return []string{WebhookEventSubscriptionDeleted}
+	default:
+		return nil
+	}
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Webhook is an endpoint that receives signed event payloads.
+// A nil UserID subscribes to events of every user.
+type Webhook struct {
+	WebhookID  uuid.UUID  `json:"webhook_id"`
+	URL        string     `json:"url"`
+	Secret     string     `json:"-"`
+	EventTypes []string   `json:"event_types"`
+	UserID     *uuid.UUID `json:"user_id"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	DeliveryID    uuid.UUID       `json:"delivery_id"`
+	WebhookID     uuid.UUID       `json:"webhook_id"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	ResponseCode  *int            `json:"response_code"`
+	LastError     *string         `json:"last_error"`
+	CreatedAt     time.Time       `json:"created_at"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	DeliveredAt   *time.Time      `json:"delivered_at"`
+}
+
+// WebhookPayload is the body POSTed to webhook endpoints.
+type WebhookPayload struct {
+	DeliveryID   uuid.UUID     `json:"delivery_id"`
+	EventType    string        `json:"event_type"`
+	OccurredAt   time.Time     `json:"occurred_at"`
+	Subscription *Subscription `json:"subscription"`
+	Previous     *Subscription `json:"previous,omitempty"`
+}
+
+// WebhookPatch holds the fields to change; nil fields are left as is.
+type WebhookPatch struct {
+	WebhookID  uuid.UUID
+	URL        *string
+	EventTypes []string
+	Active     *bool
+}
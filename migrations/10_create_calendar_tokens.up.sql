@@ -0,0 +1,5 @@
+CREATE TABLE calendar_tokens (
+    user_id UUID PRIMARY KEY,
+    token_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_subscriptions_deleted_at;
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS deleted_at;
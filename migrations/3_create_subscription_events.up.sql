@@ -0,0 +1,24 @@
+CREATE TABLE subscription_events (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL,
+    action TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    request_id TEXT NOT NULL DEFAULT '',
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    before JSONB,
+    after JSONB
+);
+
+CREATE INDEX idx_subscription_events_subscription_id ON subscription_events(subscription_id);
+CREATE INDEX idx_subscription_events_actor ON subscription_events(actor);
+CREATE INDEX idx_subscription_events_occurred_at ON subscription_events(occurred_at);
+
+CREATE FUNCTION subscription_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'subscription_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER subscription_events_no_update_delete
+    BEFORE UPDATE OR DELETE ON subscription_events
+    FOR EACH ROW EXECUTE FUNCTION subscription_events_append_only();
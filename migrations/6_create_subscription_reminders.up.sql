@@ -0,0 +1,8 @@
+CREATE TABLE subscription_reminders (
+    subscription_id UUID NOT NULL,
+    kind TEXT NOT NULL,
+    due_date DATE NOT NULL,
+    channel TEXT NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subscription_id, kind, due_date, channel)
+);
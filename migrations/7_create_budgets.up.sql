@@ -0,0 +1,19 @@
+CREATE TABLE budgets (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    service_name TEXT,
+    monthly_limit INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_budgets_user_service ON budgets(user_id, COALESCE(service_name, ''));
+
+CREATE TABLE budget_alerts (
+    id UUID PRIMARY KEY,
+    budget_id UUID NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    month DATE NOT NULL,
+    monthly_limit INTEGER NOT NULL,
+    spent INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (budget_id, month)
+);
@@ -0,0 +1,19 @@
+CREATE TABLE subscription_candidates (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    merchant TEXT NOT NULL,
+    service_name TEXT NOT NULL,
+    price INTEGER NOT NULL,
+    billing_period VARCHAR(16) NOT NULL,
+    start_date DATE NOT NULL,
+    last_charged_at DATE NOT NULL,
+    occurrences INTEGER NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    subscription_id UUID REFERENCES subscriptions(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- re-importing a statement refreshes the pending candidate instead of adding a duplicate
+CREATE UNIQUE INDEX idx_subscription_candidates_pending
+    ON subscription_candidates(user_id, merchant) WHERE status = 'pending';
@@ -0,0 +1,150 @@
+// Package client is a Go client for the subscription HTTP API. The request and response
+// types and one method per operation are generated into client.gen.go from docs/swagger.json;
+// regenerate it with go generate after changing the handler annotations and the spec.
+package client
+
+//go:generate go run ./gen -spec ../../docs/swagger.json -out client.gen.go
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	headerAPIKey = "X-API-Key"
+	headerActor  = "X-Actor"
+)
+
+// maxErrorBody bounds how much of an error response is kept.
+const maxErrorBody = 64 << 10
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	apiKey     string
+	actor      string
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces the default client, which times out after a minute.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// WithAPIKey sends key in the X-API-Key header, which gives the caller a rate limit bucket of
+// its own within the limit of its address.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.apiKey = key }
+}
+
+// WithActor sends actor in the X-Actor header, which the audit log records as the author of
+// changes.
+func WithActor(actor string) Option {
+	return func(c *Client) { c.actor = actor }
+}
+
+// New creates a client for the API served at baseURL, e.g. http://localhost:8080.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	c := &Client{baseURL: u, httpClient: &http.Client{Timeout: time.Minute}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// APIError is returned for responses with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	// Message is the plain text error of the service or the detail of a problem response.
+	Message string
+	Body    []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// Decode unmarshals the error body, for responses that describe the failure in JSON, such as
+// the per-row errors of a rejected import.
+func (e *APIError) Decode(v any) error {
+	return json.Unmarshal(e.Body, v)
+}
+
+// Ptr returns a pointer to v, for the optional fields of requests and parameters.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// do sends the request and returns the response when its status is 2xx; the caller closes
+// the body. Other responses are read and returned as *APIError.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, contentType string,
+	body io.Reader) (*http.Response, error) {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.apiKey != "" {
+		req.Header.Set(headerAPIKey, c.apiKey)
+	}
+	if c.actor != "" {
+		req.Header.Set(headerActor, c.actor)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data)), Body: data}
+	var problem struct {
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/problem+json") &&
+		json.Unmarshal(data, &problem) == nil {
+		apiErr.Message = problem.Detail
+		if apiErr.Message == "" {
+			apiErr.Message = problem.Title
+		}
+	}
+	return nil, apiErr
+}
+
+func decodeResponse(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,451 @@
+// Command gen generates the types and operations of package client from the Swagger 2.0
+// spec that swag writes to docs/swagger.json. It covers the subset of the spec swag emits
+// for this service: object definitions, path, query and body parameters and JSON, file or
+// plain text responses. Every operation needs an operationId, set with @ID.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+type spec struct {
+	Paths       map[string]map[string]*operation `json:"paths"`
+	Definitions map[string]*schema               `json:"definitions"`
+}
+
+type operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Parameters  []*parameter         `json:"parameters"`
+	Responses   map[string]*response `json:"responses"`
+
+	method, path string
+}
+
+type parameter struct {
+	Name             string  `json:"name"`
+	In               string  `json:"in"`
+	Description      string  `json:"description"`
+	Type             string  `json:"type"`
+	Items            *schema `json:"items"`
+	CollectionFormat string  `json:"collectionFormat"`
+	Schema           *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Description          string             `json:"description"`
+	Items                *schema            `json:"items"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties json.RawMessage    `json:"additionalProperties"`
+}
+
+type response struct {
+	Schema *schema `json:"schema"`
+}
+
+var httpMethods = []string{"get", "post", "put", "patch", "delete"}
+
+func main() {
+	specPath := flag.String("spec", "", "Path to swagger.json")
+	out := flag.String("out", "", "Output file")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var s spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		log.Fatalf("parse %s: %v", *specPath, err)
+	}
+
+	g := &generator{spec: &s, names: map[string]string{}, requests: map[string]bool{}}
+	src, err := g.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	formatted, err := format.Source(src)
+	if err != nil {
+		log.Fatalf("format generated code: %v\n%s", err, src)
+	}
+	if err := os.WriteFile(*out, formatted, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+type generator struct {
+	spec *spec
+	buf  bytes.Buffer
+	// names maps definition names such as domain.Subscription to Go type names.
+	names map[string]string
+	// requests holds the definitions sent as request bodies, whose scalar fields are
+	// pointers so that unset fields are left out.
+	requests map[string]bool
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) generate() ([]byte, error) {
+	ops, err := g.operations()
+	if err != nil {
+		return nil, err
+	}
+
+	taken := map[string]string{}
+	for _, def := range sortedKeys(g.spec.Definitions) {
+		name := def[strings.LastIndex(def, ".")+1:]
+		if other, ok := taken[name]; ok {
+			return nil, fmt.Errorf("definitions %s and %s map to the same type %s", other, def, name)
+		}
+		taken[name] = def
+		g.names[def] = name
+	}
+	for _, op := range ops {
+		for _, p := range op.Parameters {
+			if p.In == "body" {
+				g.markRequest(p.Schema)
+			}
+		}
+	}
+
+	for _, def := range sortedKeys(g.spec.Definitions) {
+		g.definition(def, g.spec.Definitions[def])
+	}
+	for _, op := range ops {
+		if err := g.operation(op); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(op.method), op.path, err)
+		}
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by gen from docs/swagger.json; DO NOT EDIT.\n\npackage client\n\nimport (\n")
+	for _, pkg := range []string{"bytes", "context", "encoding/json", "io", "net/url", "strconv", "strings"} {
+		used := regexp.MustCompile(`\b` + pkg[strings.LastIndex(pkg, "/")+1:] + `\.`)
+		if used.Match(g.buf.Bytes()) {
+			fmt.Fprintf(&src, "%q\n", pkg)
+		}
+	}
+	src.WriteString(")\n\n")
+	src.Write(g.buf.Bytes())
+	return src.Bytes(), nil
+}
+
+// operations returns the operations ordered by path and method.
+func (g *generator) operations() ([]*operation, error) {
+	var ops []*operation
+	ids := map[string]bool{}
+	for _, path := range sortedKeys(g.spec.Paths) {
+		for _, method := range httpMethods {
+			op, ok := g.spec.Paths[path][method]
+			if !ok {
+				continue
+			}
+			if op.OperationID == "" {
+				return nil, fmt.Errorf("%s %s has no operationId; add @ID to its annotations", strings.ToUpper(method), path)
+			}
+			if ids[op.OperationID] {
+				return nil, fmt.Errorf("operationId %s is used twice", op.OperationID)
+			}
+			ids[op.OperationID] = true
+			op.method, op.path = method, path
+			ops = append(ops, op)
+		}
+	}
+	return ops, nil
+}
+
+func (g *generator) markRequest(s *schema) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		g.requests[refName(s.Ref)] = true
+	}
+	g.markRequest(s.Items)
+}
+
+func (g *generator) definition(def string, s *schema) {
+	name := g.names[def]
+	g.printf("// %s is the %s schema.\n", name, def)
+	g.printf("type %s struct {\n", name)
+	for _, prop := range sortedKeys(s.Properties) {
+		field := s.Properties[prop]
+		if field.Description != "" {
+			g.printf("%s\n", comment(field.Description))
+		}
+		g.printf("%s %s `json:\"%s,omitempty\"`\n", goName(prop), g.goType(field, g.requests[def]), prop)
+	}
+	g.printf("}\n\n")
+}
+
+// goType maps a schema to a Go type; optional makes scalars pointers.
+func (g *generator) goType(s *schema, optional bool) string {
+	if s.Ref != "" {
+		return "*" + g.names[refName(s.Ref)]
+	}
+	ptr := ""
+	if optional {
+		ptr = "*"
+	}
+	switch s.Type {
+	case "string":
+		return ptr + "string"
+	case "integer":
+		return ptr + "int"
+	case "number":
+		return ptr + "float64"
+	case "boolean":
+		return ptr + "bool"
+	case "array":
+		return "[]" + strings.TrimPrefix(g.goType(s.Items, false), "*")
+	case "object":
+		var additional schema
+		if len(s.AdditionalProperties) > 0 && json.Unmarshal(s.AdditionalProperties, &additional) == nil &&
+			(additional.Ref != "" || additional.Type != "") {
+			return "map[string]" + g.goType(&additional, false)
+		}
+	}
+	return "json.RawMessage"
+}
+
+func (g *generator) operation(op *operation) error {
+	name := goName(op.OperationID)
+
+	var pathParams, queryParams []*parameter
+	var body *parameter
+	for _, p := range op.Parameters {
+		switch p.In {
+		case "path":
+			pathParams = append(pathParams, p)
+		case "query":
+			queryParams = append(queryParams, p)
+		case "body":
+			body = p
+		default:
+			return fmt.Errorf("unsupported %s parameter %s", p.In, p.Name)
+		}
+	}
+
+	if len(queryParams) > 0 {
+		g.printf("// %sParams are the query parameters of %s.\n", name, name)
+		g.printf("type %sParams struct {\n", name)
+		for _, p := range queryParams {
+			if p.Description != "" {
+				g.printf("%s\n", comment(p.Description))
+			}
+			g.printf("%s %s\n", goName(p.Name), g.goType(&schema{Type: p.Type, Items: p.Items}, p.Type != "array"))
+		}
+		g.printf("}\n\n")
+	}
+
+	result, decode, err := g.result(op)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"ctx context.Context"}
+	for _, p := range pathParams {
+		args = append(args, lowerFirst(goName(p.Name))+" string")
+	}
+	if len(queryParams) > 0 {
+		args = append(args, "params *"+name+"Params")
+	}
+	returns := "error"
+	if result != "" {
+		returns = "(" + result + ", error)"
+	}
+
+	// Bodies without a JSON schema are only sent raw; the others also get a typed variant.
+	rawName := name
+	if body != nil && body.Schema.Type != "string" {
+		rawName = name + "WithBody"
+		bodyType := strings.TrimPrefix(g.goType(body.Schema, false), "*")
+		callArgs := []string{"ctx"}
+		for _, p := range pathParams {
+			callArgs = append(callArgs, lowerFirst(goName(p.Name)))
+		}
+		if len(queryParams) > 0 {
+			callArgs = append(callArgs, "params")
+		}
+		callArgs = append(callArgs, `"application/json"`, "bytes.NewReader(data)")
+		zero := ""
+		if result != "" {
+			zero = zeroValue(result) + ", "
+		}
+
+		g.printf("// %s calls %s %s: %s.\n", name, strings.ToUpper(op.method), op.path, op.Summary)
+		g.printf("func (c *Client) %s(%s, body %s) %s {\n", name, strings.Join(args, ", "), bodyType, returns)
+		g.printf("data, err := json.Marshal(body)\nif err != nil {\nreturn %serr\n}\n", zero)
+		g.printf("return c.%s(%s)\n}\n\n", rawName, strings.Join(callArgs, ", "))
+	}
+	if body != nil {
+		args = append(args, "contentType string", "body io.Reader")
+	}
+
+	g.printf("// %s calls %s %s: %s.\n", rawName, strings.ToUpper(op.method), op.path, op.Summary)
+	g.printf("func (c *Client) %s(%s) %s {\n", rawName, strings.Join(args, ", "), returns)
+	g.printf("path := %s\n", pathExpr(op.path))
+	g.printf("query := url.Values{}\n")
+	if len(queryParams) > 0 {
+		g.printf("if params != nil {\n")
+		for _, p := range queryParams {
+			g.queryParam(p)
+		}
+		g.printf("}\n")
+	}
+	if body == nil {
+		g.printf("resp, err := c.do(ctx, %q, path, query, \"\", nil)\n", strings.ToUpper(op.method))
+	} else {
+		g.printf("resp, err := c.do(ctx, %q, path, query, contentType, body)\n", strings.ToUpper(op.method))
+	}
+	g.printf("%s}\n\n", decode)
+	return nil
+}
+
+// result returns the Go result type of the first successful response and the code that
+// turns resp into it.
+func (g *generator) result(op *operation) (string, string, error) {
+	for _, code := range sortedKeys(op.Responses) {
+		if !strings.HasPrefix(code, "2") {
+			continue
+		}
+		s := op.Responses[code].Schema
+		switch {
+		case s == nil:
+			return "", "if err != nil {\nreturn err\n}\nreturn resp.Body.Close()\n", nil
+		case s.Type == "file" || s.Type == "string":
+			return "io.ReadCloser", "if err != nil {\nreturn nil, err\n}\nreturn resp.Body, nil\n", nil
+		}
+		typ := g.goType(s, false)
+		value, ret := typ, "out"
+		if strings.HasPrefix(typ, "*") {
+			value, ret = typ[1:], "&out"
+		}
+		return typ, fmt.Sprintf("if err != nil {\nreturn %s, err\n}\nvar out %s\n"+
+			"if err := decodeResponse(resp, &out); err != nil {\nreturn %s, err\n}\nreturn %s, nil\n",
+			zeroValue(typ), value, zeroValue(typ), ret), nil
+	}
+	return "", "", fmt.Errorf("no successful response")
+}
+
+func (g *generator) queryParam(p *parameter) {
+	field := "params." + goName(p.Name)
+	switch p.Type {
+	case "array":
+		if p.CollectionFormat == "multi" {
+			g.printf("for _, v := range %s {\nquery.Add(%q, v)\n}\n", field, p.Name)
+		} else {
+			g.printf("if len(%s) > 0 {\nquery.Set(%q, strings.Join(%s, \",\"))\n}\n", field, p.Name, field)
+		}
+		return
+	case "integer":
+		g.printf("if %s != nil {\nquery.Set(%q, strconv.Itoa(*%s))\n}\n", field, p.Name, field)
+	case "number":
+		g.printf("if %s != nil {\nquery.Set(%q, strconv.FormatFloat(*%s, 'f', -1, 64))\n}\n", field, p.Name, field)
+	case "boolean":
+		g.printf("if %s != nil {\nquery.Set(%q, strconv.FormatBool(*%s))\n}\n", field, p.Name, field)
+	default:
+		g.printf("if %s != nil {\nquery.Set(%q, *%s)\n}\n", field, p.Name, field)
+	}
+}
+
+// pathExpr builds the request path, escaping every path parameter.
+func pathExpr(path string) string {
+	var parts []string
+	for path != "" {
+		start := strings.Index(path, "{")
+		if start < 0 {
+			parts = append(parts, fmt.Sprintf("%q", path))
+			break
+		}
+		end := strings.Index(path, "}")
+		if start > 0 {
+			parts = append(parts, fmt.Sprintf("%q", path[:start]))
+		}
+		parts = append(parts, fmt.Sprintf("url.PathEscape(%s)", lowerFirst(goName(path[start+1:end]))))
+		path = path[end+1:]
+	}
+	return strings.Join(parts, " + ")
+}
+
+func zeroValue(typ string) string {
+	if strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") ||
+		typ == "json.RawMessage" || typ == "io.ReadCloser" {
+		return "nil"
+	}
+	return typ + "{}"
+}
+
+func refName(ref string) string {
+	return strings.TrimPrefix(ref, "#/definitions/")
+}
+
+var initialisms = map[string]string{"id": "ID", "url": "URL", "ics": "ICS", "api": "API", "csv": "CSV", "graphql": "GraphQL"}
+
+// goName turns snake_case, kebab-case and camelCase names into exported Go identifiers.
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' })
+	var b strings.Builder
+	for _, word := range words {
+		for _, part := range splitCamel(word) {
+			if initialism, ok := initialisms[strings.ToLower(part)]; ok {
+				b.WriteString(initialism)
+				continue
+			}
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+func splitCamel(word string) []string {
+	var parts []string
+	start := 0
+	for i, r := range word {
+		if i > 0 && unicode.IsUpper(r) {
+			parts = append(parts, word[start:i])
+			start = i
+		}
+	}
+	return append(parts, word[start:])
+}
+
+func lowerFirst(name string) string {
+	for initialism := range initialisms {
+		upper := strings.ToUpper(initialism)
+		if name == upper {
+			return initialism
+		}
+		if strings.HasPrefix(name, upper) {
+			return initialism + name[len(upper):]
+		}
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+func comment(text string) string {
+	return "// " + strings.ReplaceAll(strings.TrimSpace(text), "\n", "\n// ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -1,7 +1,7 @@
 package config
 
 import (
-	"log"
+	"fmt"
 	"os"
 	"strings"
 
@@ -9,20 +9,39 @@ import (
 	"github.com/kasparovgs/subscription-aggregation-service/cmd/app/config"
 )
 
-func MustLoad(cfgPath string, cfg *config.AppConfig) {
-	if cfgPath == "" {
-		log.Fatal("Config path is not set")
+// Load reads the config file, when cfgPath is set, and the environment, which takes
+// precedence, and validates the result. Without a file every setting comes from the
+// environment and the defaults.
+func Load(cfgPath string, cfg *config.AppConfig) error {
+	if cfgPath != "" {
+		if _, err := os.Stat(cfgPath); os.IsNotExist(err) {
+			return fmt.Errorf("config file does not exist by this path: %s", cfgPath)
+		}
+		if err := cleanenv.ReadConfig(cfgPath, cfg); err != nil {
+			return fmt.Errorf("error reading config: %w", err)
+		}
+	} else if err := cleanenv.ReadEnv(cfg); err != nil {
+		return fmt.Errorf("error reading environment: %w", err)
 	}
 
-	if _, err := os.Stat(cfgPath); os.IsNotExist(err) {
-		log.Fatalf("config file does not exist by this path: %s", cfgPath)
+	if !strings.HasPrefix(cfg.HTTPConfig.Address, ":") {
+		cfg.HTTPConfig.Address = ":" + cfg.HTTPConfig.Address
+	}
+	if !strings.HasPrefix(cfg.GRPCConfig.Address, ":") {
+		cfg.GRPCConfig.Address = ":" + cfg.GRPCConfig.Address
 	}
 
-	if err := cleanenv.ReadConfig(cfgPath, cfg); err != nil {
-		log.Fatalf("error reading config: %s", err)
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config:\n%w", indent(err))
 	}
+	return nil
+}
 
-	if !strings.HasPrefix(cfg.HTTPConfig.Address, ":") {
-		cfg.HTTPConfig.Address = ":" + cfg.HTTPConfig.Address
+// indent puts every line of the aggregated validation errors on its own bullet.
+func indent(err error) error {
+	lines := strings.Split(err.Error(), "\n")
+	for i, line := range lines {
+		lines[i] = "  - " + line
 	}
+	return fmt.Errorf("%s", strings.Join(lines, "\n"))
 }
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+// Print writes cfg as YAML in the layout of config.yml. Fields tagged secret:"true" are
+// replaced when set, and durations are written the way they are configured, e.g. 30s.
+// Fields without a yaml key, which can only be set from the environment, are keyed by
+// their lower-cased variable name.
+func Print(w io.Writer, cfg any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(encode(reflect.ValueOf(cfg))); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func encode(v reflect.Value) *yaml.Node {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return scalar("!!null", "null")
+		}
+		v = v.Elem()
+	}
+	if v.Type() == durationType {
+		return scalar("!!str", time.Duration(v.Int()).String())
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		encodeFields(node, v)
+		return node
+	case reflect.Map:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) })
+		for _, key := range keys {
+			node.Content = append(node.Content, scalar("!!str", key.String()), encode(v.MapIndex(key)))
+		}
+		return node
+	case reflect.Slice, reflect.Array:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for i := range v.Len() {
+			node.Content = append(node.Content, encode(v.Index(i)))
+		}
+		return node
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v.Interface()); err != nil {
+			return scalar("!!str", fmt.Sprint(v.Interface()))
+		}
+		return node
+	}
+}
+
+func encodeFields(node *yaml.Node, v reflect.Value) {
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := fieldKey(field)
+		if key == "-" {
+			continue
+		}
+		if key == "" && field.Anonymous {
+			encodeFields(node, v.Field(i))
+			continue
+		}
+
+		value := encode(v.Field(i))
+		if field.Tag.Get("secret") == "true" && !v.Field(i).IsZero() {
+			value = scalar("!!str", redacted)
+		}
+		node.Content = append(node.Content, scalar("!!str", key), value)
+	}
+}
+
+func fieldKey(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("yaml"), ","); name != "" {
+		return name
+	}
+	if env := field.Tag.Get("env"); env != "" {
+		return strings.ToLower(env)
+	}
+	if field.Anonymous {
+		return ""
+	}
+	return strings.ToLower(field.Name)
+}
+
+func scalar(tag, value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+}
@@ -0,0 +1,172 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/ratelimit"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const HeaderAPIKey = "X-API-Key"
+
+// RateLimitConfig describes the limits of a RateLimiter.
+type RateLimitConfig struct {
+	// Default is the limit of a client on the routes without a limit of their own.
+	Default ratelimit.Limit
+	// Routes are keyed by "METHOD /chi/pattern" and get a bucket of their own per client.
+	Routes map[string]ratelimit.Limit
+	// Address bounds all requests from one address, whatever client they claim to be.
+	Address ratelimit.Limit
+	// TrustedProxies are the proxies whose X-Forwarded-For is believed.
+	TrustedProxies []netip.Prefix
+}
+
+// RateLimiter limits requests with token buckets. Every request takes a token from the
+// bucket of its address and one from the bucket of its client on the route. The client is
+// the API key or the X-Actor user when given, else the address. Neither header is
+// authenticated, so the address bucket is what stops a client that changes them on every
+// request; the client buckets share what one address may send fairly between the users
+// behind it.
+type RateLimiter struct {
+	store  ratelimit.Store
+	router *chi.Mux
+	cfg    RateLimitConfig
+}
+
+func NewRateLimiter(store ratelimit.Store, router *chi.Mux, cfg RateLimitConfig) *RateLimiter {
+	return &RateLimiter{store: store, router: router, cfg: cfg}
+}
+
+// Middleware counts the request against its buckets, sets the RateLimit-* headers of the
+// tighter one and answers 429 once a bucket is empty. A failing store lets the request through.
+func (l *RateLimiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The route is matched here already because the limit depends on it.
+		route := l.router.Find(chi.NewRouteContext(), r.Method, r.URL.Path)
+		limit, bucket := l.cfg.Default, "default"
+		if routeLimit, ok := l.cfg.Routes[r.Method+" "+route]; ok && route != "" {
+			limit, bucket = routeLimit, r.Method+" "+route
+		}
+		if limit.Unlimited() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// The address bucket goes first, so a client that is out of it does not get a client
+		// bucket stored for every header value it makes up.
+		now := time.Now()
+		address := "ip:" + l.clientIP(r)
+		var (
+			res     ratelimit.Result
+			applied ratelimit.Limit
+			err     error
+		)
+		if !l.cfg.Address.Unlimited() {
+			res, err = l.store.Take(r.Context(), address+"|address", l.cfg.Address, now)
+			applied = l.cfg.Address
+		}
+		if err == nil && (applied.Unlimited() || res.Allowed) {
+			var clientRes ratelimit.Result
+			clientRes, err = l.store.Take(r.Context(), l.clientKey(r, address)+"|"+bucket, limit, now)
+			if err == nil && (applied.Unlimited() || !clientRes.Allowed || clientRes.Remaining <= res.Remaining) {
+				res, applied = clientRes, limit
+			}
+		}
+		if err != nil {
+			slog.ErrorContext(r.Context(), "rate limit store failed", "layer", "http_handler", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(res.Reset)))
+		h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", applied.Requests, ceilSeconds(applied.Period)))
+		if !res.Allowed {
+			metrics.HTTPRateLimited.WithLabelValues(r.Method, route).Inc()
+			slog.WarnContext(r.Context(), "rate limit exceeded",
+				"layer", "http_handler",
+				"bucket", bucket,
+				"retry_after", res.RetryAfter)
+			h.Set("Retry-After", strconv.Itoa(ceilSeconds(res.RetryAfter)))
+			WriteProblem(w, &Problem{
+				Type:     "about:blank",
+				Title:    http.StatusText(http.StatusTooManyRequests),
+				Status:   http.StatusTooManyRequests,
+				Detail:   fmt.Sprintf("rate limit of %d requests per %s exceeded", applied.Requests, applied.Period),
+				Instance: r.URL.Path,
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientKey identifies the caller by API key, then by the X-Actor user, then by address.
+// The headers are scoped to the address, so the same header from another address is
+// another client. API keys are hashed so the store never holds them in clear.
+func (l *RateLimiter) clientKey(r *http.Request, address string) string {
+	if apiKey := r.Header.Get(HeaderAPIKey); apiKey != "" {
+		sum := sha256.Sum256([]byte(apiKey))
+		return address + "|key:" + hex.EncodeToString(sum[:16])
+	}
+	if actor := r.Header.Get(HeaderActor); actor != "" {
+		return address + "|user:" + actor
+	}
+	return address
+}
+
+// clientIP returns the address of the peer, or, when the peer is a trusted proxy, the
+// rightmost X-Forwarded-For address that is not a trusted proxy. The entries left of it are
+// whatever the client sent and are never believed.
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !l.trusted(addr) {
+		return host
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(forwarded[i]))
+		if err != nil {
+			// A malformed entry can't be followed further; the last good address stands.
+			break
+		}
+		addr = hop.Unmap()
+		if !l.trusted(addr) {
+			break
+		}
+	}
+	return addr.String()
+}
+
+func (l *RateLimiter) trusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range l.cfg.TrustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/ratelimit"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func newTestRouter(cfg RateLimitConfig) *chi.Mux {
+	r := chi.NewRouter()
+	r.Use(NewRateLimiter(ratelimit.NewMemoryStore(), r, cfg).Middleware)
+	ok := func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) }
+	r.Get("/subscriptions", ok)
+	r.Get("/subscriptions/total", ok)
+	return r
+}
+
+// send makes a request from remoteAddr with the given headers.
+func send(router http.Handler, path, remoteAddr string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func perMinute(requests int) ratelimit.Limit {
+	return ratelimit.Limit{Requests: requests, Period: time.Minute}
+}
+
+func TestRateLimiterSetsHeadersAndRejects(t *testing.T) {
+	router := newTestRouter(RateLimitConfig{Default: perMinute(2)})
+
+	for i, wantRemaining := range []string{"1", "0"} {
+		rec := send(router, "/subscriptions", "192.0.2.1:1234", nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d; want 200", i+1, rec.Code)
+		}
+		h := rec.Header()
+		if h.Get("RateLimit-Limit") != "2" || h.Get("RateLimit-Remaining") != wantRemaining ||
+			h.Get("RateLimit-Policy") != "2;w=60" || h.Get("RateLimit-Reset") == "" {
+			t.Fatalf("request %d: headers %v", i+1, h)
+		}
+	}
+
+	rec := send(router, "/subscriptions", "192.0.2.1:1234", nil)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("third request: status %d; want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "30" {
+		t.Errorf("Retry-After %q; want 30", got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("Content-Type %q; want application/problem+json", got)
+	}
+	var problem Problem
+	if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil || problem.Status != http.StatusTooManyRequests {
+		t.Errorf("problem %+v, %v; want status 429", problem, err)
+	}
+
+	if rec := send(router, "/subscriptions", "192.0.2.2:1234", nil); rec.Code != http.StatusOK {
+		t.Errorf("another address: status %d; want 200", rec.Code)
+	}
+}
+
+func TestRateLimiterKeepsRouteBucketsApart(t *testing.T) {
+	router := newTestRouter(RateLimitConfig{
+		Default: perMinute(1),
+		Routes:  map[string]ratelimit.Limit{"GET /subscriptions/total": perMinute(1)},
+	})
+
+	if rec := send(router, "/subscriptions", "192.0.2.1:1234", nil); rec.Code != http.StatusOK {
+		t.Fatalf("default route: status %d; want 200", rec.Code)
+	}
+	if rec := send(router, "/subscriptions/total", "192.0.2.1:1234", nil); rec.Code != http.StatusOK {
+		t.Fatalf("own route: status %d; want 200", rec.Code)
+	}
+	if rec := send(router, "/subscriptions/total", "192.0.2.1:1234", nil); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("own route again: status %d; want 429", rec.Code)
+	}
+}
+
+func TestRateLimiterHeadersDoNotBuyFreshBuckets(t *testing.T) {
+	router := newTestRouter(RateLimitConfig{Default: perMinute(2), Address: perMinute(3)})
+
+	for i := 0; i < 3; i++ {
+		rec := send(router, "/subscriptions", "192.0.2.1:1234", map[string]string{HeaderAPIKey: fmt.Sprint("key-", i)})
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d; want 200", i+1, rec.Code)
+		}
+	}
+	rec := send(router, "/subscriptions", "192.0.2.1:1234", map[string]string{HeaderActor: "someone-new"})
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("fourth request with a new identity: status %d; want 429", rec.Code)
+	}
+	if got := rec.Header().Get("RateLimit-Policy"); got != "3;w=60" {
+		t.Errorf("RateLimit-Policy %q; want the address limit 3;w=60", got)
+	}
+}
+
+func TestRateLimiterSharesAnAddressBetweenUsers(t *testing.T) {
+	router := newTestRouter(RateLimitConfig{Default: perMinute(2), Address: perMinute(10)})
+	alice := map[string]string{HeaderActor: "alice"}
+
+	for i := 0; i < 2; i++ {
+		if rec := send(router, "/subscriptions", "192.0.2.1:1234", alice); rec.Code != http.StatusOK {
+			t.Fatalf("alice request %d: status %d; want 200", i+1, rec.Code)
+		}
+	}
+	if rec := send(router, "/subscriptions", "192.0.2.1:1234", alice); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("alice past her limit: status %d; want 429", rec.Code)
+	}
+	if rec := send(router, "/subscriptions", "192.0.2.1:1234", map[string]string{HeaderActor: "bob"}); rec.Code != http.StatusOK {
+		t.Fatalf("bob on the same address: status %d; want 200", rec.Code)
+	}
+}
+
+func TestRateLimiterClientIP(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), chi.NewRouter(), RateLimitConfig{
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	})
+	tests := []struct {
+		name       string
+		remoteAddr string
+		forwarded  string
+		want       string
+	}{
+		{"direct client", "192.0.2.1:1234", "", "192.0.2.1"},
+		{"untrusted peer sends a forwarded header", "192.0.2.1:1234", "198.51.100.7", "192.0.2.1"},
+		{"trusted proxy", "10.0.0.1:1234", "198.51.100.7", "198.51.100.7"},
+		{"client-made entries left of the proxy's", "10.0.0.1:1234", "203.0.113.9, 198.51.100.7", "198.51.100.7"},
+		{"chain of trusted proxies", "10.0.0.1:1234", "203.0.113.9, 198.51.100.7, 10.0.0.2", "198.51.100.7"},
+		{"only trusted proxies", "10.0.0.1:1234", "10.0.0.3", "10.0.0.3"},
+		{"malformed entry on the right", "10.0.0.1:1234", "198.51.100.7, garbage", "10.0.0.1"},
+		{"no forwarded header", "10.0.0.1:1234", "", "10.0.0.1"},
+		{"IPv6 peer", "[2001:db8::1]:1234", "198.51.100.7", "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if got := limiter.clientIP(req); got != tt.want {
+				t.Fatalf("got %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterIgnoresSpoofedForwardedEntries(t *testing.T) {
+	router := newTestRouter(RateLimitConfig{
+		Default:        perMinute(2),
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	})
+
+	for i := 0; i < 3; i++ {
+		// The proxy appends the real client address to whatever the client sent.
+		forwarded := fmt.Sprintf("203.0.113.%d, 198.51.100.7", i)
+		rec := send(router, "/subscriptions", "10.0.0.1:1234", map[string]string{"X-Forwarded-For": forwarded})
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if rec.Code != want {
+			t.Fatalf("request %d: status %d; want %d", i+1, rec.Code, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package http
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/reqctx"
+)
+
+// Problem is an RFC 7807 problem details response.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// WriteProblem sends p as application/problem+json.
+func WriteProblem(w http.ResponseWriter, p *Problem) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// RecoveryMiddleware turns a panic in a handler into a 500 problem response and logs it with
+// the stack, instead of dropping the connection.
+func RecoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+			slog.ErrorContext(r.Context(), "panic while serving request",
+				"layer", "http_handler",
+				"panic", rec,
+				"stack", string(debug.Stack()))
+			WriteProblem(w, &Problem{
+				Type:      "about:blank",
+				Title:     http.StatusText(http.StatusInternalServerError),
+				Status:    http.StatusInternalServerError,
+				Detail:    "the server failed to handle the request",
+				Instance:  r.URL.Path,
+				RequestID: reqctx.RequestID(r.Context()),
+			})
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
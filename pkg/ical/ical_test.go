@@ -0,0 +1,95 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// unfold joins folded lines back, as calendar apps read them.
+func unfold(s string) []string {
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(s, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+func writeCalendar(t *testing.T, c *Calendar) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := c.Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return buf.String()
+}
+
+func TestWriteEscapesText(t *testing.T) {
+	tests := []struct {
+		name    string
+		summary string
+		want    string
+	}{
+		{"plain", "Netflix renews", `SUMMARY:Netflix renews`},
+		{"special characters", `Plan; basic, \monthly`, `SUMMARY:Plan\; basic\, \\monthly`},
+		{"CRLF", "first\r\nsecond", `SUMMARY:first\nsecond`},
+		{"LF", "first\nsecond", `SUMMARY:first\nsecond`},
+		{"bare CR", "first\rBEGIN:VALARM", `SUMMARY:first\nBEGIN:VALARM`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := writeCalendar(t, &Calendar{
+				ProdID: "-//test//EN",
+				Name:   "Subscriptions",
+				Events: []Event{{UID: "1@test", Date: time.Date(2025, time.July, 31, 0, 0, 0, 0, time.UTC), Summary: tt.summary}},
+			})
+			if strings.Count(out, "\r") != strings.Count(out, "\r\n") {
+				t.Fatalf("bare CR in output:\n%q", out)
+			}
+			var summary string
+			for _, line := range unfold(out) {
+				if strings.HasPrefix(line, "SUMMARY:") {
+					summary = line
+				}
+				if line == "BEGIN:VALARM" {
+					t.Fatalf("text value started a content line of its own:\n%q", out)
+				}
+			}
+			if summary != tt.want {
+				t.Fatalf("got %q; want %q", summary, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteFoldsLongLines(t *testing.T) {
+	description := strings.Repeat("Подписка на Яндекс Плюс, ", 10)
+	out := writeCalendar(t, &Calendar{
+		ProdID: "-//test//EN",
+		Name:   "Subscriptions",
+		Events: []Event{{
+			UID:         "1@test",
+			Date:        time.Date(2025, time.July, 31, 0, 0, 0, 0, time.UTC),
+			Summary:     "Yandex Plus",
+			Description: description,
+		}},
+	})
+
+	if !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Fatalf("calendar does not end with CRLF:\n%q", out)
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line %d has %d octets: %q", i+1, len(line), line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line %d splits a UTF-8 sequence: %q", i+1, line)
+		}
+	}
+	want := "DESCRIPTION:" + escapeText(description)
+	found := false
+	for _, line := range unfold(out) {
+		found = found || line == want
+	}
+	if !found {
+		t.Fatalf("unfolded output has no %q:\n%s", want, out)
+	}
+}
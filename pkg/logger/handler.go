@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/reqctx"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/tracing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// contextHandler adds what the record's context knows about the request being served: the
+// request id, the actor, the chi route and the current trace and span, so every line written
+// while handling a request can be tied to it.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if requestID := reqctx.RequestID(ctx); requestID != "" {
+		r.AddAttrs(slog.String("request_id", requestID), slog.String("actor", reqctx.Actor(ctx)))
+	}
+	if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+		r.AddAttrs(slog.String("route", rctx.RoutePattern()))
+	}
+	if traceID, spanID := tracing.IDs(ctx); traceID != "" {
+		r.AddAttrs(slog.String("trace_id", traceID), slog.String("span_id", spanID))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,27 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+// LogNotifier writes reminders to the application log.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, reminder domain.Reminder) error {
+	slog.Info("subscription reminder",
+		"kind", reminder.Kind,
+		"due_date", reminder.DueDate.Format(time.DateOnly),
+		"subscription_id", reminder.Subscription.SubscriptionID,
+		"user_id", reminder.Subscription.UserID,
+		"service_name", reminder.Subscription.ServiceName,
+		"price", reminder.Subscription.Price)
+	return nil
+}
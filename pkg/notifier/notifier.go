@@ -0,0 +1,19 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+// Notifier delivers a reminder to the user.
+type Notifier interface {
+	Notify(ctx context.Context, reminder domain.Reminder) error
+}
+
+// Channel is a notifier with the name its sends are recorded under, so that a reminder that
+// failed on one channel is retried there without being repeated on the others.
+type Channel struct {
+	Name string
+	Notifier
+}
@@ -0,0 +1,139 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// SMTPNotifier e-mails reminders to a fixed list of recipients.
+// Authentication is used only when a username is set.
+type SMTPNotifier struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+	timeout  time.Duration
+}
+
+// NewSMTPNotifier creates a notifier for the server at host:port. timeout bounds connecting and
+// the whole exchange with the server, so a server that stops responding cannot block the caller.
+func NewSMTPNotifier(host string, port int, username, password, from string, to []string,
+	timeout time.Duration) *SMTPNotifier {
+	return &SMTPNotifier{
+		addr:     net.JoinHostPort(host, strconv.Itoa(port)),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+		to:       to,
+		timeout:  timeout,
+	}
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, reminder domain.Reminder) error {
+	if err := n.send(ctx, n.message(reminder)); err != nil {
+		return fmt.Errorf("send reminder mail: %w", err)
+	}
+	return nil
+}
+
+// send does what smtp.SendMail does, on a connection bounded by the timeout and ctx.
+func (n *SMTPNotifier) send(ctx context.Context, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// Cancelling ctx interrupts a pending read or write.
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, n.host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.host}); err != nil {
+			return err
+		}
+	}
+	if n.username != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.username, n.password, n.host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(n.from); err != nil {
+		return err
+	}
+	for _, to := range n.to {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message builds the mail. The service name comes from users, so line breaks are removed
+// from it and the subject is Q-encoded, which keeps it from adding headers or sending raw
+// 8-bit bytes in the header.
+func (n *SMTPNotifier) message(reminder domain.Reminder) []byte {
+	subs := reminder.Subscription
+	due := reminder.DueDate.Format(time.DateOnly)
+	service := lineBreaks.Replace(subs.ServiceName)
+
+	var subject, text string
+	switch reminder.Kind {
+	case domain.ReminderKindExpiry:
+		subject = fmt.Sprintf("%s subscription ends on %s", service, due)
+		text = fmt.Sprintf("The %s subscription of user %s ends on %s.", service, subs.UserID, due)
+	default:
+		subject = fmt.Sprintf("%s subscription renews on %s", service, due)
+		text = fmt.Sprintf("The %s subscription of user %s renews on %s for %d.", service, subs.UserID, due, subs.Price)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(text)
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
@@ -0,0 +1,246 @@
+package notifier
+
+import (
+	"context"
+	"mime"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+// fakeSMTPServer accepts mail on a local port without STARTTLS or AUTH and keeps what it got.
+// A hanging server accepts connections and never answers.
+type fakeSMTPServer struct {
+	ln      net.Listener
+	hanging bool
+
+	mu   sync.Mutex
+	from string
+	to   []string
+	data string
+}
+
+func startFakeSMTPServer(t *testing.T, hanging bool) *fakeSMTPServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTPServer{ln: ln, hanging: hanging}
+	t.Cleanup(func() { _ = ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) hostPort(t *testing.T) (string, int) {
+	t.Helper()
+	host, port, err := net.SplitHostPort(s.ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return host, p
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+	if s.hanging {
+		// Holds the connection open until the client gives up.
+		_, _ = conn.Read(make([]byte, 1))
+		return
+	}
+
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250-localhost")
+			_ = tp.PrintfLine("250 8BITMIME")
+		case "MAIL":
+			s.mu.Lock()
+			s.from = mailPath(arg, "FROM:")
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 OK")
+		case "RCPT":
+			s.mu.Lock()
+			s.to = append(s.to, mailPath(arg, "TO:"))
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 OK")
+		case "DATA":
+			_ = tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			lines, err := tp.ReadDotLines()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.data = strings.Join(lines, "\n")
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 OK")
+		case "QUIT":
+			_ = tp.PrintfLine("221 Bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+// mailPath takes the address out of a MAIL or RCPT argument and drops its parameters.
+func mailPath(arg, prefix string) string {
+	path, _, _ := strings.Cut(strings.TrimPrefix(arg, prefix), " ")
+	return strings.Trim(path, "<>")
+}
+
+func testReminder() domain.Reminder {
+	end := time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)
+	return domain.Reminder{
+		Subscription: domain.Subscription{
+			SubscriptionID: uuid.New(),
+			ServiceName:    "Netflix",
+			Price:          799,
+			UserID:         uuid.New(),
+			StartDate:      time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:        &end,
+		},
+		Kind:    domain.ReminderKindExpiry,
+		DueDate: time.Date(2025, time.July, 31, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestSMTPNotifierSendsReminder(t *testing.T) {
+	server := startFakeSMTPServer(t, false)
+	host, port := server.hostPort(t)
+	to := []string{"alice@example.com", "bob@example.com"}
+	n := NewSMTPNotifier(host, port, "", "", "reminders@example.com", to, 5*time.Second)
+
+	if err := n.Notify(context.Background(), testReminder()); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if server.from != "reminders@example.com" {
+		t.Errorf("MAIL FROM %q; want reminders@example.com", server.from)
+	}
+	if strings.Join(server.to, ",") != strings.Join(to, ",") {
+		t.Errorf("RCPT TO %v; want %v", server.to, to)
+	}
+	if !strings.Contains(server.data, "Subject: Netflix subscription ends on 2025-07-31") {
+		t.Errorf("message has no expected subject:\n%s", server.data)
+	}
+}
+
+func TestSMTPNotifierEncodesSubject(t *testing.T) {
+	tests := []struct {
+		name        string
+		serviceName string
+		wantSubject string
+	}{
+		{
+			name:        "header injection",
+			serviceName: "Evil\r\nBcc: attacker@example.com",
+			wantSubject: "Evil Bcc: attacker@example.com subscription ends on 2025-07-31",
+		},
+		{
+			name:        "non-ASCII",
+			serviceName: "Кинопоиск",
+			wantSubject: "Кинопоиск subscription ends on 2025-07-31",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := startFakeSMTPServer(t, false)
+			host, port := server.hostPort(t)
+			n := NewSMTPNotifier(host, port, "", "", "reminders@example.com", []string{"alice@example.com"}, 5*time.Second)
+			reminder := testReminder()
+			reminder.Subscription.ServiceName = tt.serviceName
+
+			if err := n.Notify(context.Background(), reminder); err != nil {
+				t.Fatalf("Notify: %v", err)
+			}
+
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			header, _, _ := strings.Cut(server.data, "\n\n")
+			var subject string
+			for _, line := range strings.Split(header, "\n") {
+				if strings.HasPrefix(line, "Bcc:") {
+					t.Errorf("injected header %q", line)
+				}
+				if encoded, ok := strings.CutPrefix(line, "Subject: "); ok {
+					decoded, err := new(mime.WordDecoder).DecodeHeader(encoded)
+					if err != nil {
+						t.Fatalf("decode subject %q: %v", encoded, err)
+					}
+					subject = decoded
+				}
+				for _, r := range line {
+					if r > 127 {
+						t.Errorf("header line has non-ASCII bytes: %q", line)
+						break
+					}
+				}
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("got %q; want %q", subject, tt.wantSubject)
+			}
+		})
+	}
+}
+
+func TestSMTPNotifierTimesOutOnHangingServer(t *testing.T) {
+	server := startFakeSMTPServer(t, true)
+	host, port := server.hostPort(t)
+	n := NewSMTPNotifier(host, port, "", "", "reminders@example.com", []string{"alice@example.com"}, 200*time.Millisecond)
+
+	start := time.Now()
+	if err := n.Notify(context.Background(), testReminder()); err == nil {
+		t.Fatal("Notify succeeded against a server that never answers")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Notify returned after %v; want about the 200ms timeout", elapsed)
+	}
+}
+
+func TestSMTPNotifierStopsWhenContextIsCancelled(t *testing.T) {
+	server := startFakeSMTPServer(t, true)
+	host, port := server.hostPort(t)
+	n := NewSMTPNotifier(host, port, "", "", "reminders@example.com", []string{"alice@example.com"}, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := n.Notify(ctx, testReminder()); err == nil {
+		t.Fatal("Notify succeeded against a server that never answers")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Notify returned after %v; want it to stop with the context", elapsed)
+	}
+}
@@ -0,0 +1,13 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+// Publisher delivers an outbox message to downstream consumers.
+// Delivery is at-least-once, so consumers should deduplicate by MessageID.
+type Publisher interface {
+	Publish(ctx context.Context, msg domain.OutboxMessage) error
+}
@@ -0,0 +1,89 @@
+// Package ratelimit implements token bucket rate limiting with pluggable bucket storage.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit allows Requests per Period on average with bursts of up to Burst requests.
+// A limit with no requests does not limit anything.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate is the refill speed in tokens per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result describes the bucket after a request was counted.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed; zero when Allowed.
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets. MemoryStore serves a single instance; a shared implementation,
+// e.g. on Redis, lets several instances enforce one limit.
+type Store interface {
+	// Take removes a token from the bucket of key, refilled up to now.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// Bucket is the state of one token bucket.
+type Bucket struct {
+	Tokens  float64
+	Updated time.Time
+}
+
+// Consume refills the bucket for the time passed since its last update and takes a token
+// when there is one. Stores call it while holding whatever lock protects the bucket.
+func (b *Bucket) Consume(limit Limit, now time.Time) Result {
+	capacity, rate := limit.capacity(), limit.rate()
+	if b.Updated.IsZero() {
+		b.Tokens = capacity
+	} else if elapsed := now.Sub(b.Updated).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+elapsed*rate)
+	}
+	b.Updated = now
+
+	res := Result{Limit: int(capacity)}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.Tokens) / rate)
+	}
+	res.Remaining = int(b.Tokens)
+	res.Reset = seconds((capacity - b.Tokens) / rate)
+	return res
+}
+
+// Full reports whether the bucket has refilled completely by now, which makes it
+// indistinguishable from a new one.
+func (b *Bucket) Full(limit Limit, now time.Time) bool {
+	return b.Tokens+now.Sub(b.Updated).Seconds()*limit.rate() >= limit.capacity()
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,126 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// oneASecond refills one token per second into a bucket of three.
+var oneASecond = Limit{Requests: 60, Period: time.Minute, Burst: 3}
+
+func TestBucketConsume(t *testing.T) {
+	start := time.Date(2025, time.July, 1, 12, 0, 0, 0, time.UTC)
+	var b Bucket
+
+	steps := []struct {
+		name           string
+		at             time.Duration
+		wantAllowed    bool
+		wantRemaining  int
+		wantRetryAfter time.Duration
+		wantReset      time.Duration
+	}{
+		{"new bucket is full", 0, true, 2, 0, time.Second},
+		{"burst", 0, true, 1, 0, 2 * time.Second},
+		{"last token", 0, true, 0, 0, 3 * time.Second},
+		{"empty", 0, false, 0, time.Second, 3 * time.Second},
+		{"half refilled", 500 * time.Millisecond, false, 0, 500 * time.Millisecond, 2500 * time.Millisecond},
+		{"one token refilled", time.Second, true, 0, 0, 3 * time.Second},
+		{"refill stops at capacity", time.Hour, true, 2, 0, time.Second},
+	}
+	for _, step := range steps {
+		res := b.Consume(oneASecond, start.Add(step.at))
+		if res.Allowed != step.wantAllowed || res.Remaining != step.wantRemaining || res.Limit != 3 {
+			t.Fatalf("%s: allowed %v, remaining %d, limit %d; want %v, %d, 3",
+				step.name, res.Allowed, res.Remaining, res.Limit, step.wantAllowed, step.wantRemaining)
+		}
+		if res.RetryAfter != step.wantRetryAfter || res.Reset != step.wantReset {
+			t.Fatalf("%s: retry after %v, reset %v; want %v, %v",
+				step.name, res.RetryAfter, res.Reset, step.wantRetryAfter, step.wantReset)
+		}
+	}
+}
+
+func TestBucketConsumeWithoutBurst(t *testing.T) {
+	limit := Limit{Requests: 2, Period: time.Minute}
+	now := time.Now()
+	var b Bucket
+	for i := 0; i < 2; i++ {
+		if res := b.Consume(limit, now); !res.Allowed || res.Limit != 2 {
+			t.Fatalf("request %d: %+v; want allowed with a limit of 2", i+1, res)
+		}
+	}
+	if res := b.Consume(limit, now); res.Allowed || res.RetryAfter != 30*time.Second {
+		t.Fatalf("third request: %+v; want denied for 30s", res)
+	}
+}
+
+func TestBucketFull(t *testing.T) {
+	now := time.Now()
+	var b Bucket
+	b.Consume(oneASecond, now)
+
+	if b.Full(oneASecond, now.Add(999*time.Millisecond)) {
+		t.Fatal("bucket is full before its token came back")
+	}
+	if !b.Full(oneASecond, now.Add(time.Second)) {
+		t.Fatal("bucket is not full after its token came back")
+	}
+}
+
+func TestLimitUnlimited(t *testing.T) {
+	tests := []struct {
+		limit Limit
+		want  bool
+	}{
+		{Limit{Requests: 10, Period: time.Minute}, false},
+		{Limit{Requests: 0, Period: time.Minute}, true},
+		{Limit{Requests: 10}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.limit.Unlimited(); got != tt.want {
+			t.Errorf("%+v: Unlimited() = %v; want %v", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if res, err := store.Take(ctx, "a", oneASecond, now); err != nil || !res.Allowed {
+			t.Fatalf("request %d of a: %+v, %v; want allowed", i+1, res, err)
+		}
+	}
+	if res, _ := store.Take(ctx, "a", oneASecond, now); res.Allowed {
+		t.Fatal("a is allowed past its burst")
+	}
+	if res, _ := store.Take(ctx, "b", oneASecond, now); !res.Allowed {
+		t.Fatal("b shares the bucket of a")
+	}
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	now := time.Now()
+
+	_, _ = store.Take(ctx, "idle", oneASecond, now)
+	for i := 0; i < 3; i++ {
+		_, _ = store.Take(ctx, "busy", oneASecond, now.Add(sweepInterval-time.Second))
+	}
+	// The next take sweeps: idle has refilled long ago, busy is still short of tokens.
+	_, _ = store.Take(ctx, "new", oneASecond, now.Add(sweepInterval))
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("full bucket was not swept")
+	}
+	if _, ok := store.buckets["busy"]; !ok {
+		t.Error("bucket that is not full was swept")
+	}
+}
@@ -0,0 +1,163 @@
+package statement
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+var (
+	dateColumns        = []string{"date", "transaction date", "posting date", "posted", "дата", "дата операции"}
+	descriptionColumns = []string{"description", "merchant", "payee", "name", "memo", "описание", "назначение"}
+	amountColumns      = []string{"amount", "sum", "сумма", "сумма операции"}
+)
+
+var (
+	// yearFirstLayouts read the same whatever the order of day and month elsewhere in the file.
+	yearFirstLayouts  = []string{"2006-01-02", "2006/01/02", time.RFC3339}
+	dayFirstLayouts   = append([]string{"02.01.2006", "02/01/2006"}, yearFirstLayouts...)
+	monthFirstLayouts = append([]string{"01.02.2006", "01/02/2006"}, yearFirstLayouts...)
+)
+
+// ParseCSV reads a CSV statement with a header line. The date, description and amount
+// columns are found by their usual names; the separator may be a comma or a semicolon.
+// Whether dates put the day or the month first is decided once for the whole file, see
+// dateOrder. Amounts may use a decimal comma or point and spaces, commas or points as
+// thousands separators.
+func ParseCSV(r io.Reader) ([]domain.BankTransaction, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+	dateCol, descCol, amountCol := findColumn(header, dateColumns), findColumn(header, descriptionColumns),
+		findColumn(header, amountColumns)
+	if dateCol < 0 || descCol < 0 || amountCol < 0 {
+		return nil, errors.New("csv header must have date, description and amount columns")
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+	layouts, err := dateLayouts(records, dateCol)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []domain.BankTransaction
+	for i, record := range records {
+		line := i + 2
+		if len(record) <= max(dateCol, descCol, amountCol) {
+			return nil, fmt.Errorf("line %d: not enough columns", line)
+		}
+		date, err := parseDate(record[dateCol], layouts)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		amount, err := parseAmount(record[amountCol])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		transactions = append(transactions, domain.BankTransaction{
+			Date:        date,
+			Description: strings.TrimSpace(record[descCol]),
+			Amount:      amount,
+		})
+	}
+	return transactions, nil
+}
+
+func findColumn(header []string, names []string) int {
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for _, name := range names {
+			if h == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// dateLayouts looks at every date of the column to tell whether the file writes 05/03/2025
+// day first or month first: a number above 12 can only be the day, whichever place it is in.
+// Files where every date reads both ways are taken as day first.
+func dateLayouts(records [][]string, col int) ([]string, error) {
+	var dayFirst, monthFirst bool
+	for _, record := range records {
+		if col >= len(record) {
+			continue
+		}
+		parts := strings.FieldsFunc(strings.TrimSpace(record[col]), func(r rune) bool { return r == '.' || r == '/' })
+		if len(parts) != 3 || len(parts[2]) != 4 {
+			continue
+		}
+		first, err1 := strconv.Atoi(parts[0])
+		second, err2 := strconv.Atoi(parts[1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		dayFirst = dayFirst || first > 12
+		monthFirst = monthFirst || second > 12
+	}
+	switch {
+	case dayFirst && monthFirst:
+		return nil, errors.New("csv dates mix day-first and month-first order")
+	case monthFirst:
+		return monthFirstLayouts, nil
+	default:
+		return dayFirstLayouts, nil
+	}
+}
+
+func parseDate(s string, layouts []string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date format %q", s)
+}
+
+// parseAmount takes the last comma or point as the decimal separator and any before it as
+// thousands separators, so 1.234,56 and 1,234.56 are both 1234.56. A separator that repeats,
+// as in 1,234,567, separates thousands only.
+func parseAmount(s string) (float64, error) {
+	raw := s
+	s = strings.NewReplacer(" ", "", "\u00a0", "", "'", "").Replace(strings.TrimSpace(s))
+	if i := strings.LastIndexAny(s, ",."); i >= 0 {
+		thousands := strings.NewReplacer(",", "", ".", "")
+		if strings.IndexByte(s[:i], s[i]) >= 0 {
+			s = thousands.Replace(s)
+		} else {
+			s = thousands.Replace(s[:i]) + "." + s[i+1:]
+		}
+	}
+	amount, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", raw)
+	}
+	return amount, nil
+}
@@ -0,0 +1,106 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name        string
+		csv         string
+		wantDates   []time.Time
+		wantAmounts []float64
+		wantErr     string
+	}{
+		{
+			name:        "ISO dates and decimal points",
+			csv:         "Date,Description,Amount\n2025-01-05,NETFLIX.COM,-15.49\n2025-02-05,NETFLIX.COM,-15.49\n",
+			wantDates:   []time.Time{date(2025, time.January, 5), date(2025, time.February, 5)},
+			wantAmounts: []float64{-15.49, -15.49},
+		},
+		{
+			name:        "Russian export with a BOM, semicolons and decimal commas",
+			csv:         "\xef\xbb\xbfДата операции;Описание;Сумма операции\n05.01.2025;Яндекс Плюс;-299,00\n05.02.2025;Яндекс Плюс;-1 299,50\n",
+			wantDates:   []time.Time{date(2025, time.January, 5), date(2025, time.February, 5)},
+			wantAmounts: []float64{-299, -1299.5},
+		},
+		{
+			name:        "month first when a second number is above 12",
+			csv:         "Posting Date,Payee,Amount\n01/05/2025,Spotify,-9.99\n01/15/2025,Spotify,-9.99\n",
+			wantDates:   []time.Time{date(2025, time.January, 5), date(2025, time.January, 15)},
+			wantAmounts: []float64{-9.99, -9.99},
+		},
+		{
+			name:        "day first when a first number is above 12",
+			csv:         "Date,Payee,Amount\n05/01/2025,Spotify,-9.99\n15/01/2025,Spotify,-9.99\n",
+			wantDates:   []time.Time{date(2025, time.January, 5), date(2025, time.January, 15)},
+			wantAmounts: []float64{-9.99, -9.99},
+		},
+		{
+			name:        "day first when every date reads both ways",
+			csv:         "Date,Payee,Amount\n05/01/2025,Spotify,-9.99\n",
+			wantDates:   []time.Time{date(2025, time.January, 5)},
+			wantAmounts: []float64{-9.99},
+		},
+		{
+			name:    "mixed date orders",
+			csv:     "Date,Payee,Amount\n13/01/2025,Spotify,-9.99\n01/13/2025,Spotify,-9.99\n",
+			wantErr: "mix day-first and month-first",
+		},
+		{
+			name:        "thousands separators",
+			csv:         "date;memo;sum\n2025-01-05;Rent;-1.234,56\n2025-01-06;Salary;1,234.56\n2025-01-07;Car;1,234,567\n2025-01-08;Tax;-1'234.5\n",
+			wantDates:   []time.Time{date(2025, time.January, 5), date(2025, time.January, 6), date(2025, time.January, 7), date(2025, time.January, 8)},
+			wantAmounts: []float64{-1234.56, 1234.56, 1234567, -1234.5},
+		},
+		{
+			name:    "missing column",
+			csv:     "Date,Description\n2025-01-05,NETFLIX.COM\n",
+			wantErr: "must have date, description and amount columns",
+		},
+		{
+			name:    "short line",
+			csv:     "Date,Description,Amount\n2025-01-05,NETFLIX.COM\n",
+			wantErr: "line 2: not enough columns",
+		},
+		{
+			name:    "unknown date",
+			csv:     "Date,Description,Amount\n2025-01-05,NETFLIX.COM,-15.49\nJan 5,NETFLIX.COM,-15.49\n",
+			wantErr: `line 3: unknown date format "Jan 5"`,
+		},
+		{
+			name:    "invalid amount",
+			csv:     "Date,Description,Amount\n2025-01-05,NETFLIX.COM,free\n",
+			wantErr: `line 2: invalid amount "free"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactions, err := ParseCSV(strings.NewReader(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got %v; want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(transactions) != len(tt.wantDates) {
+				t.Fatalf("got %d transactions; want %d", len(transactions), len(tt.wantDates))
+			}
+			for i, tr := range transactions {
+				if !tr.Date.Equal(tt.wantDates[i]) || tr.Amount != tt.wantAmounts[i] || tr.Description == "" {
+					t.Errorf("transaction %d: %+v; want date %s and amount %v", i, tr,
+						tt.wantDates[i].Format(time.DateOnly), tt.wantAmounts[i])
+				}
+			}
+		})
+	}
+}
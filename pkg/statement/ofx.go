@@ -0,0 +1,69 @@
+package statement
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+var (
+	ofxTransactionStart = regexp.MustCompile(`(?i)<STMTTRN>`)
+	// ofxTransactionEnd matches what may close a record: OFX 1.x leaves STMTTRN unclosed.
+	ofxTransactionEnd = regexp.MustCompile(`(?i)</STMTTRN>|</BANKTRANLIST>`)
+	// ofxField matches both the SGML form of OFX 1.x (<NAME>value) and the XML form of 2.x.
+	ofxField = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<\r\n]*)`)
+)
+
+// ParseOFX reads the STMTTRN records of an OFX or QFX file.
+func ParseOFX(r io.Reader) ([]domain.BankTransaction, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []domain.BankTransaction
+	records := ofxTransactionStart.Split(string(data), -1)
+	for _, record := range records[1:] {
+		if loc := ofxTransactionEnd.FindStringIndex(record); loc != nil {
+			record = record[:loc[0]]
+		}
+		fields := make(map[string]string)
+		for _, f := range ofxField.FindAllStringSubmatch(record, -1) {
+			fields[strings.ToUpper(f[1])] = strings.TrimSpace(f[2])
+		}
+
+		date, err := parseOFXDate(fields["DTPOSTED"])
+		if err != nil {
+			return nil, err
+		}
+		amount, err := parseAmount(fields["TRNAMT"])
+		if err != nil {
+			return nil, err
+		}
+		description := fields["NAME"]
+		if description == "" {
+			description = fields["MEMO"]
+		}
+		transactions = append(transactions, domain.BankTransaction{Date: date, Description: description, Amount: amount})
+	}
+	if len(transactions) == 0 {
+		return nil, fmt.Errorf("no transactions found in ofx")
+	}
+	return transactions, nil
+}
+
+// parseOFXDate reads the YYYYMMDD prefix of an OFX datetime such as 20250105120000.000[-5:EST].
+func parseOFXDate(s string) (time.Time, error) {
+	if len(s) < 8 {
+		return time.Time{}, fmt.Errorf("invalid ofx date %q", s)
+	}
+	t, err := time.Parse("20060102", s[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid ofx date %q", s)
+	}
+	return t, nil
+}
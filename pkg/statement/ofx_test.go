@@ -0,0 +1,88 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+func TestParseOFX(t *testing.T) {
+	tests := []struct {
+		name    string
+		ofx     string
+		want    []domain.BankTransaction
+		wantErr string
+	}{
+		{
+			name: "OFX 1.x leaves records unclosed",
+			ofx: `OFXHEADER:100
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250105120000.000[-5:EST]
+<TRNAMT>-15.49
+<NAME>NETFLIX.COM
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250205
+<TRNAMT>-15.49
+<MEMO>NETFLIX.COM 866-579-7172
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`,
+			want: []domain.BankTransaction{
+				{Date: date(2025, time.January, 5), Description: "NETFLIX.COM", Amount: -15.49},
+				{Date: date(2025, time.February, 5), Description: "NETFLIX.COM 866-579-7172", Amount: -15.49},
+			},
+		},
+		{
+			name: "OFX 2.x XML",
+			ofx: `<?xml version="1.0"?><OFX><BANKTRANLIST>` +
+				`<STMTTRN><DTPOSTED>20250301</DTPOSTED><TRNAMT>-9.99</TRNAMT><NAME>Spotify</NAME><MEMO>P123</MEMO></STMTTRN>` +
+				`<stmttrn><dtposted>20250401</dtposted><trnamt>2500.00</trnamt><name>Salary</name></stmttrn>` +
+				`</BANKTRANLIST></OFX>`,
+			want: []domain.BankTransaction{
+				{Date: date(2025, time.March, 1), Description: "Spotify", Amount: -9.99},
+				{Date: date(2025, time.April, 1), Description: "Salary", Amount: 2500},
+			},
+		},
+		{
+			name:    "no records",
+			ofx:     "<OFX><BANKTRANLIST></BANKTRANLIST></OFX>",
+			wantErr: "no transactions found",
+		},
+		{
+			name:    "short date",
+			ofx:     "<STMTTRN><DTPOSTED>2025</DTPOSTED><TRNAMT>-1</TRNAMT></STMTTRN>",
+			wantErr: `invalid ofx date "2025"`,
+		},
+		{
+			name:    "invalid amount",
+			ofx:     "<STMTTRN><DTPOSTED>20250105</DTPOSTED><TRNAMT></TRNAMT></STMTTRN>",
+			wantErr: `invalid amount ""`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactions, err := ParseOFX(strings.NewReader(tt.ofx))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got %v; want an error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(transactions) != len(tt.want) {
+				t.Fatalf("got %d transactions; want %d", len(transactions), len(tt.want))
+			}
+			for i, tr := range transactions {
+				want := tt.want[i]
+				if !tr.Date.Equal(want.Date) || tr.Description != want.Description || tr.Amount != want.Amount {
+					t.Errorf("transaction %d: %+v; want %+v", i, tr, want)
+				}
+			}
+		})
+	}
+}
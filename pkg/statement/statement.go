@@ -0,0 +1,28 @@
+// Package statement reads bank statement files into transactions.
+package statement
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+const (
+	FormatCSV = "csv"
+	FormatOFX = "ofx"
+	FormatQFX = "qfx"
+)
+
+// Parse reads a statement in the given format. QFX is OFX with Quicken extensions and
+// is read the same way.
+func Parse(format string, r io.Reader) ([]domain.BankTransaction, error) {
+	switch format {
+	case FormatCSV:
+		return ParseCSV(r)
+	case FormatOFX, FormatQFX:
+		return ParseOFX(r)
+	default:
+		return nil, fmt.Errorf("unsupported statement format %q", format)
+	}
+}
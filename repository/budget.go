@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+type BudgetDB interface {
+	CreateBudget(ctx context.Context, budget *domain.Budget) error
+	GetBudgetByID(ctx context.Context, budgetID uuid.UUID) (*domain.Budget, error)
+	// GetListOfBudgets returns the budgets of userID, or all budgets when userID is nil.
+	GetListOfBudgets(ctx context.Context, userID *uuid.UUID) ([]domain.Budget, error)
+	PatchBudgetByID(ctx context.Context, budget *domain.Budget) error
+	DeleteBudgetByID(ctx context.Context, budgetID uuid.UUID) error
+
+	// CreateBudgetAlert records the alert and reports false when one exists for the budget and month.
+	CreateBudgetAlert(ctx context.Context, alert *domain.BudgetAlert) (bool, error)
+	GetListOfBudgetAlerts(ctx context.Context, userID uuid.UUID, month time.Time) ([]domain.BudgetAlert, error)
+}
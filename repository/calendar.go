@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+type CalendarTokenDB interface {
+	// SaveCalendarToken stores the token of the user, replacing the previous one.
+	SaveCalendarToken(ctx context.Context, token *domain.CalendarToken) error
+	GetCalendarToken(ctx context.Context, userID uuid.UUID) (*domain.CalendarToken, error)
+	DeleteCalendarToken(ctx context.Context, userID uuid.UUID) error
+}
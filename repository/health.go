@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+type HealthDB interface {
+	Ping(ctx context.Context) error
+	// GetSchemaVersion returns the migration version recorded by migrate and whether the
+	// last migration failed halfway.
+	GetSchemaVersion(ctx context.Context) (version uint, dirty bool, err error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+type OutboxDB interface {
+	// ClaimOutboxMessages returns up to limit pending messages and hides them from other relays for lease.
+	ClaimOutboxMessages(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error)
+	MarkOutboxMessagePublished(ctx context.Context, messageID uuid.UUID) error
+	MarkOutboxMessageFailed(ctx context.Context, messageID uuid.UUID, reason string, nextAttemptAt time.Time) error
+}
@@ -0,0 +1,145 @@
+package postgres_storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+func (ps *SubcriptionDB) CreateBudget(ctx context.Context, budget *domain.Budget) error {
+	defer metrics.ObserveQuery("CreateBudget")()
+	query := `INSERT INTO budgets (id, user_id, service_name, monthly_limit, created_at)
+			  VALUES ($1, $2, $3, $4, $5)`
+	_, err := ps.db.ExecContext(ctx, query, budget.BudgetID, budget.UserID, budget.ServiceName, budget.MonthlyLimit, budget.CreatedAt)
+	if isUniqueViolation(err) {
+		return domain.ErrAlreadyExist("budget for this user and service")
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ps *SubcriptionDB) GetBudgetByID(ctx context.Context, budgetID uuid.UUID) (*domain.Budget, error) {
+	defer metrics.ObserveQuery("GetBudgetByID")()
+	query := `SELECT id, user_id, service_name, monthly_limit, created_at FROM budgets WHERE id = $1`
+	var b domain.Budget
+	err := ps.db.QueryRowContext(ctx, query, budgetID).Scan(&b.BudgetID, &b.UserID, &b.ServiceName, &b.MonthlyLimit, &b.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound("budget not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (ps *SubcriptionDB) GetListOfBudgets(ctx context.Context, userID *uuid.UUID) ([]domain.Budget, error) {
+	defer metrics.ObserveQuery("GetListOfBudgets")()
+	builder := sq.Select("id", "user_id", "service_name", "monthly_limit", "created_at").
+		From("budgets").
+		OrderBy("created_at").
+		PlaceholderFormat(sq.Dollar)
+	if userID != nil {
+		builder = builder.Where(sq.Eq{"user_id": *userID})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := ps.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var budgets []domain.Budget
+	for rows.Next() {
+		var b domain.Budget
+		if err := rows.Scan(&b.BudgetID, &b.UserID, &b.ServiceName, &b.MonthlyLimit, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets, rows.Err()
+}
+
+func (ps *SubcriptionDB) PatchBudgetByID(ctx context.Context, budget *domain.Budget) error {
+	defer metrics.ObserveQuery("PatchBudgetByID")()
+	query := `UPDATE budgets SET monthly_limit = $1 WHERE id = $2`
+	res, err := ps.db.ExecContext(ctx, query, budget.MonthlyLimit, budget.BudgetID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound("budget not found")
+	}
+	return nil
+}
+
+func (ps *SubcriptionDB) DeleteBudgetByID(ctx context.Context, budgetID uuid.UUID) error {
+	defer metrics.ObserveQuery("DeleteBudgetByID")()
+	res, err := ps.db.ExecContext(ctx, `DELETE FROM budgets WHERE id = $1`, budgetID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound("budget not found")
+	}
+	return nil
+}
+
+func (ps *SubcriptionDB) CreateBudgetAlert(ctx context.Context, alert *domain.BudgetAlert) (bool, error) {
+	defer metrics.ObserveQuery("CreateBudgetAlert")()
+	query := `INSERT INTO budget_alerts (id, budget_id, month, monthly_limit, spent, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6)
+			  ON CONFLICT (budget_id, month) DO NOTHING`
+	res, err := ps.db.ExecContext(ctx, query, alert.AlertID, alert.BudgetID, alert.Month, alert.MonthlyLimit, alert.Spent, alert.CreatedAt)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (ps *SubcriptionDB) GetListOfBudgetAlerts(ctx context.Context, userID uuid.UUID, month time.Time) ([]domain.BudgetAlert, error) {
+	defer metrics.ObserveQuery("GetListOfBudgetAlerts")()
+	query := `SELECT a.id, a.budget_id, a.month, a.monthly_limit, a.spent, a.created_at
+			  FROM budget_alerts a JOIN budgets b ON b.id = a.budget_id
+			  WHERE b.user_id = $1 AND a.month = $2`
+	rows, err := ps.db.QueryContext(ctx, query, userID, month)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var alerts []domain.BudgetAlert
+	for rows.Next() {
+		var a domain.BudgetAlert
+		if err := rows.Scan(&a.AlertID, &a.BudgetID, &a.Month, &a.MonthlyLimit, &a.Spent, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a)
+	}
+	return alerts, rows.Err()
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
@@ -0,0 +1,45 @@
+package postgres_storage
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+
+	"github.com/google/uuid"
+)
+
+func (ps *SubcriptionDB) SaveCalendarToken(ctx context.Context, token *domain.CalendarToken) error {
+	defer metrics.ObserveQuery("SaveCalendarToken")()
+	query := `INSERT INTO calendar_tokens (user_id, token_hash, created_at) VALUES ($1, $2, $3)
+			  ON CONFLICT (user_id) DO UPDATE SET token_hash = EXCLUDED.token_hash, created_at = EXCLUDED.created_at`
+	_, err := ps.db.ExecContext(ctx, query, token.UserID, token.TokenHash, token.CreatedAt)
+	return err
+}
+
+func (ps *SubcriptionDB) GetCalendarToken(ctx context.Context, userID uuid.UUID) (*domain.CalendarToken, error) {
+	defer metrics.ObserveQuery("GetCalendarToken")()
+	query := `SELECT user_id, token_hash, created_at FROM calendar_tokens WHERE user_id = $1`
+	var t domain.CalendarToken
+	err := ps.db.QueryRowContext(ctx, query, userID).Scan(&t.UserID, &t.TokenHash, &t.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound("calendar token not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (ps *SubcriptionDB) DeleteCalendarToken(ctx context.Context, userID uuid.UUID) error {
+	defer metrics.ObserveQuery("DeleteCalendarToken")()
+	res, err := ps.db.ExecContext(ctx, `DELETE FROM calendar_tokens WHERE user_id = $1`, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound("calendar token not found")
+	}
+	return nil
+}
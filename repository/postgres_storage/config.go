@@ -0,0 +1,88 @@
+package postgres_storage
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config holds the connection settings and pool limits of the database.
+type Config struct {
+	// DSN, when set, is used instead of the connection pieces.
+	DSN      string
+	Host     string
+	Port     int
+	Name     string
+	User     string
+	Password string
+	SSLMode  string
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// StatementTimeout is sent as the statement_timeout run-time parameter of every
+	// connection unless the DSN already sets it; 0 leaves the server default.
+	StatementTimeout time.Duration
+
+	// ConnectTimeout bounds how long startup waits for the database to accept connections,
+	// retrying every ConnectBackoff, doubled after each attempt up to ConnectMaxBackoff.
+	ConnectTimeout    time.Duration
+	ConnectBackoff    time.Duration
+	ConnectMaxBackoff time.Duration
+	// ReadRetries is how many times a SELECT outside a transaction is repeated after a
+	// transient connection error, first after ReadRetryBackoff; 0 disables retries.
+	ReadRetries      int
+	ReadRetryBackoff time.Duration
+}
+
+// ConnString returns the lib/pq connection string.
+func (c Config) ConnString() string {
+	timeout := ""
+	if c.StatementTimeout > 0 {
+		timeout = strconv.FormatInt(c.StatementTimeout.Milliseconds(), 10)
+	}
+
+	if c.DSN != "" {
+		if timeout == "" || strings.Contains(c.DSN, "statement_timeout") {
+			return c.DSN
+		}
+		if u, err := url.Parse(c.DSN); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+			q := u.Query()
+			q.Set("statement_timeout", timeout)
+			u.RawQuery = q.Encode()
+			return u.String()
+		}
+		return c.DSN + " statement_timeout=" + timeout
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(c.User, c.Password),
+		Host:   net.JoinHostPort(c.Host, strconv.Itoa(c.Port)),
+		Path:   "/" + c.Name,
+	}
+	q := url.Values{}
+	if c.SSLMode != "" {
+		q.Set("sslmode", c.SSLMode)
+	}
+	if timeout != "" {
+		q.Set("statement_timeout", timeout)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// String describes the target without credentials, for logs.
+func (c Config) String() string {
+	if c.DSN != "" {
+		if u, err := url.Parse(c.DSN); err == nil && u.Host != "" {
+			return fmt.Sprintf("%s%s", u.Host, u.Path)
+		}
+		return "dsn"
+	}
+	return fmt.Sprintf("%s/%s", net.JoinHostPort(c.Host, strconv.Itoa(c.Port)), c.Name)
+}
@@ -0,0 +1,29 @@
+package postgres_storage
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+)
+
+func (ps *SubcriptionDB) Ping(ctx context.Context) error {
+	return ps.pool.PingContext(ctx)
+}
+
+func (ps *SubcriptionDB) GetSchemaVersion(ctx context.Context) (uint, bool, error) {
+	defer metrics.ObserveQuery("GetSchemaVersion")()
+	query := `SELECT version, dirty FROM schema_migrations LIMIT 1`
+	var (
+		version uint
+		dirty   bool
+	)
+	err := ps.db.QueryRowContext(ctx, query).Scan(&version, &dirty)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return version, dirty, nil
+}
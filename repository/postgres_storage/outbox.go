@@ -0,0 +1,73 @@
+package postgres_storage
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+
+	"github.com/google/uuid"
+)
+
+func insertOutboxMessage(ctx context.Context, tx dbtx, eventType string, subs *domain.Subscription) error {
+	payload, err := json.Marshal(subs)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO outbox (id, event_type, aggregate_id, payload) VALUES ($1, $2, $3, $4)`
+	_, err = tx.ExecContext(ctx, query, uuid.New(), eventType, subs.SubscriptionID, string(payload))
+	return err
+}
+
+func (ps *SubcriptionDB) ClaimOutboxMessages(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	defer metrics.ObserveQuery("ClaimOutboxMessages")()
+	query := `UPDATE outbox SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond', attempts = attempts + 1
+			  WHERE id IN (
+				  SELECT id FROM outbox
+				  WHERE published_at IS NULL AND next_attempt_at <= NOW()
+				  ORDER BY created_at
+				  LIMIT $1
+				  FOR UPDATE SKIP LOCKED
+			  )
+			  RETURNING id, event_type, aggregate_id, payload, created_at, attempts`
+	rows, err := ps.db.QueryContext(ctx, query, limit, lease.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []domain.OutboxMessage
+	for rows.Next() {
+		var m domain.OutboxMessage
+		var payload []byte
+		err = rows.Scan(&m.MessageID, &m.EventType, &m.AggregateID, &payload, &m.CreatedAt, &m.Attempts)
+		if err != nil {
+			return nil, err
+		}
+		m.Payload = payload
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// RETURNING does not keep the subquery order.
+	sort.Slice(messages, func(i, j int) bool { return messages[i].CreatedAt.Before(messages[j].CreatedAt) })
+	return messages, nil
+}
+
+func (ps *SubcriptionDB) MarkOutboxMessagePublished(ctx context.Context, messageID uuid.UUID) error {
+	defer metrics.ObserveQuery("MarkOutboxMessagePublished")()
+	query := `UPDATE outbox SET published_at = NOW(), last_error = NULL WHERE id = $1`
+	_, err := ps.db.ExecContext(ctx, query, messageID)
+	return err
+}
+
+func (ps *SubcriptionDB) MarkOutboxMessageFailed(ctx context.Context, messageID uuid.UUID, reason string, nextAttemptAt time.Time) error {
+	defer metrics.ObserveQuery("MarkOutboxMessageFailed")()
+	query := `UPDATE outbox SET last_error = $1, next_attempt_at = $2 WHERE id = $3`
+	_, err := ps.db.ExecContext(ctx, query, reason, nextAttemptAt, messageID)
+	return err
+}
@@ -0,0 +1,107 @@
+package postgres_storage
+
+import (
+	"context"
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// CreatePriceChange schedules the change and records it as an update of the subscription,
+// with the schedule before and after it in the snapshots.
+func (ps *SubcriptionDB) CreatePriceChange(ctx context.Context, change *domain.PriceChange) error {
+	defer metrics.ObserveQuery("CreatePriceChange")()
+	return ps.withTx(ctx, func(tx dbtx) error {
+		before, err := lockPriceSchedule(ctx, tx, change.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		query := `INSERT INTO subscription_price_changes (id, subscription_id, effective_from, price, created_at)
+				  VALUES ($1, $2, $3, $4, $5)`
+		_, err = tx.ExecContext(ctx, query, change.PriceChangeID, change.SubscriptionID, change.EffectiveFrom, change.Price,
+			change.CreatedAt)
+		if isUniqueViolation(err) {
+			return domain.ErrAlreadyExist("price change for this month")
+		}
+		if err != nil {
+			return err
+		}
+		return recordPriceScheduleChange(ctx, tx, domain.EventActionPriceChangeScheduled, before)
+	})
+}
+
+func (ps *SubcriptionDB) GetListOfPriceChanges(ctx context.Context, subscriptionIDs []uuid.UUID) ([]domain.PriceChange, error) {
+	defer metrics.ObserveQuery("GetListOfPriceChanges")()
+	return listPriceChanges(ctx, ps.db, subscriptionIDs)
+}
+
+// DeletePriceChangeByID removes the change and records it like CreatePriceChange does.
+func (ps *SubcriptionDB) DeletePriceChangeByID(ctx context.Context, subscriptionID, priceChangeID uuid.UUID) error {
+	defer metrics.ObserveQuery("DeletePriceChangeByID")()
+	return ps.withTx(ctx, func(tx dbtx) error {
+		before, err := lockPriceSchedule(ctx, tx, subscriptionID)
+		if err != nil {
+			return err
+		}
+		query := `DELETE FROM subscription_price_changes WHERE id = $1 AND subscription_id = $2`
+		res, err := tx.ExecContext(ctx, query, priceChangeID, subscriptionID)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return domain.ErrNotFound("price change not found")
+		}
+		return recordPriceScheduleChange(ctx, tx, domain.EventActionPriceChangeDeleted, before)
+	})
+}
+
+func listPriceChanges(ctx context.Context, db dbtx, subscriptionIDs []uuid.UUID) ([]domain.PriceChange, error) {
+	ids := make([]string, 0, len(subscriptionIDs))
+	for _, id := range subscriptionIDs {
+		ids = append(ids, id.String())
+	}
+	query := `SELECT id, subscription_id, effective_from, price, created_at FROM subscription_price_changes
+			  WHERE subscription_id = ANY($1::uuid[])
+			  ORDER BY effective_from`
+	rows, err := db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []domain.PriceChange
+	for rows.Next() {
+		var c domain.PriceChange
+		if err := rows.Scan(&c.PriceChangeID, &c.SubscriptionID, &c.EffectiveFrom, &c.Price, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// lockPriceSchedule locks the subscription like lockSubscription and reads its price changes,
+// the state a change of the schedule is recorded against.
+func lockPriceSchedule(ctx context.Context, tx dbtx, subscriptionID uuid.UUID) (*domain.Subscription, error) {
+	subs, err := lockSubscription(ctx, tx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subs.PriceChanges, err = listPriceChanges(ctx, tx, []uuid.UUID{subscriptionID}); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// recordPriceScheduleChange reads the schedule again after a change and records the change of
+// the subscription from before.
+func recordPriceScheduleChange(ctx context.Context, tx dbtx, action string, before *domain.Subscription) error {
+	after := *before
+	var err error
+	if after.PriceChanges, err = listPriceChanges(ctx, tx, []uuid.UUID{before.SubscriptionID}); err != nil {
+		return err
+	}
+	return recordChange(ctx, tx, domain.EventSubscriptionUpdated, action, before, &after)
+}
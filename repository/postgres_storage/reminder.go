@@ -0,0 +1,80 @@
+package postgres_storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+func (ps *SubcriptionDB) GetSubscriptionsEndingBetween(ctx context.Context, from, to time.Time) ([]domain.Subscription, error) {
+	defer metrics.ObserveQuery("GetSubscriptionsEndingBetween")()
+	builder := sq.Select("id", "service_name", "price", "user_id", "start_date", "end_date", "billing_period").
+		From("subscriptions").
+		Where("deleted_at IS NULL").
+		Where(sq.GtOrEq{"end_date": from}).
+		Where(sq.LtOrEq{"end_date": to}).
+		PlaceholderFormat(sq.Dollar)
+	return ps.querySubscriptions(ctx, builder)
+}
+
+func (ps *SubcriptionDB) GetOpenEndedSubscriptions(ctx context.Context, startedBy time.Time) ([]domain.Subscription, error) {
+	defer metrics.ObserveQuery("GetOpenEndedSubscriptions")()
+	builder := sq.Select("id", "service_name", "price", "user_id", "start_date", "end_date", "billing_period").
+		From("subscriptions").
+		Where("deleted_at IS NULL").
+		Where("end_date IS NULL").
+		Where(sq.LtOrEq{"start_date": startedBy}).
+		PlaceholderFormat(sq.Dollar)
+	return ps.querySubscriptions(ctx, builder)
+}
+
+func (ps *SubcriptionDB) ClaimReminder(ctx context.Context, reminder *domain.Reminder, channel string) (bool, error) {
+	defer metrics.ObserveQuery("ClaimReminder")()
+	query := `INSERT INTO subscription_reminders (subscription_id, kind, due_date, channel) VALUES ($1, $2, $3, $4)
+			  ON CONFLICT DO NOTHING`
+	res, err := ps.db.ExecContext(ctx, query, reminder.Subscription.SubscriptionID, reminder.Kind, reminder.DueDate, channel)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+func (ps *SubcriptionDB) ReleaseReminder(ctx context.Context, reminder *domain.Reminder, channel string) error {
+	defer metrics.ObserveQuery("ReleaseReminder")()
+	query := `DELETE FROM subscription_reminders WHERE subscription_id = $1 AND kind = $2 AND due_date = $3 AND channel = $4`
+	_, err := ps.db.ExecContext(ctx, query, reminder.Subscription.SubscriptionID, reminder.Kind, reminder.DueDate, channel)
+	return err
+}
+
+func (ps *SubcriptionDB) querySubscriptions(ctx context.Context, builder sq.SelectBuilder) ([]domain.Subscription, error) {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := ps.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []domain.Subscription
+	for rows.Next() {
+		var s domain.Subscription
+		err = rows.Scan(&s.SubscriptionID, &s.ServiceName,
+			&s.Price, &s.UserID, &s.StartDate, &s.EndDate, &s.BillingPeriod)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	return subs, rows.Err()
+}
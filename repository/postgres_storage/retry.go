@@ -0,0 +1,123 @@
+package postgres_storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// retryDB retries SELECT statements on the pool when they fail with a transient connection
+// error. It is never used inside a transaction: a broken connection aborts the transaction,
+// and only the caller can decide whether to run it again. database/sql already retries
+// driver.ErrBadConn on a fresh connection; this covers restarts and failovers that take longer.
+type retryDB struct {
+	dbtx
+	retries int
+	// backoff is the wait before the first retry; it doubles for every further retry.
+	backoff time.Duration
+}
+
+func (r retryDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	rows, err := r.dbtx.QueryContext(ctx, query, args...)
+	for attempt := 1; r.retryable(ctx, query, err, attempt); attempt++ {
+		rows, err = r.dbtx.QueryContext(ctx, query, args...)
+	}
+	return rows, err
+}
+
+func (r retryDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	row := r.dbtx.QueryRowContext(ctx, query, args...)
+	for attempt := 1; r.retryable(ctx, query, row.Err(), attempt); attempt++ {
+		row = r.dbtx.QueryRowContext(ctx, query, args...)
+	}
+	return row
+}
+
+// retryable waits before the given retry attempt and reports whether it should be made.
+func (r retryDB) retryable(ctx context.Context, query string, err error, attempt int) bool {
+	if err == nil || attempt > r.retries || queryOperation(query) != "SELECT" || !isTransient(err) {
+		return false
+	}
+	slog.WarnContext(ctx, "retrying read after transient database error",
+		"layer", "repository", "attempt", attempt, "error", err)
+	return sleep(ctx, backoff(attempt, r.backoff, r.backoff<<r.retries))
+}
+
+// connect pings the database until it answers, waiting with exponential backoff between
+// attempts. Errors that retrying cannot fix, such as bad credentials, are returned at once.
+func connect(db *sql.DB, cfg Config) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		if !isTransient(err) {
+			return err
+		}
+		wait := backoff(attempt, cfg.ConnectBackoff, cfg.ConnectMaxBackoff)
+		slog.Warn("database is not available yet", "database", cfg.String(), "attempt", attempt,
+			"retry_in", wait, "error", err)
+		if !sleep(ctx, wait) {
+			return fmt.Errorf("database is not available after %s: %w", cfg.ConnectTimeout, err)
+		}
+	}
+}
+
+// isTransient reports whether err means the connection, not the statement, failed, so the
+// same statement may succeed on another connection.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "57P01", "57P02", "57P03": // admin_shutdown, crash_shutdown, cannot_connect_now
+			return true
+		}
+		return pqErr.Code.Class() == "08" // connection_exception
+	}
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.As(err, &netErr)
+}
+
+// backoff doubles base for every previous attempt, capped at max.
+func backoff(attempts int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempts && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
+
+// sleep waits for d and reports false when ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,131 @@
+package postgres_storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+const candidateColumns = `id, user_id, merchant, service_name, price, billing_period, start_date, last_charged_at,
+	occurrences, status, subscription_id, created_at, updated_at`
+
+func scanSubscriptionCandidate(row rowScanner) (*domain.SubscriptionCandidate, error) {
+	var c domain.SubscriptionCandidate
+	err := row.Scan(&c.CandidateID, &c.UserID, &c.Merchant, &c.ServiceName, &c.Price, &c.BillingPeriod, &c.StartDate,
+		&c.LastChargedAt, &c.Occurrences, &c.Status, &c.SubscriptionID, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (ps *SubcriptionDB) SaveSubscriptionCandidate(ctx context.Context, candidate *domain.SubscriptionCandidate) error {
+	defer metrics.ObserveQuery("SaveSubscriptionCandidate")()
+	query := `INSERT INTO subscription_candidates (id, user_id, merchant, service_name, price, billing_period,
+				  start_date, last_charged_at, occurrences, status)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, 'pending')
+			  ON CONFLICT (user_id, merchant) WHERE status = 'pending' DO UPDATE SET
+				  service_name = EXCLUDED.service_name,
+				  price = EXCLUDED.price,
+				  billing_period = EXCLUDED.billing_period,
+				  start_date = LEAST(subscription_candidates.start_date, EXCLUDED.start_date),
+				  last_charged_at = GREATEST(subscription_candidates.last_charged_at, EXCLUDED.last_charged_at),
+				  occurrences = GREATEST(subscription_candidates.occurrences, EXCLUDED.occurrences),
+				  updated_at = NOW()
+			  RETURNING ` + candidateColumns
+	saved, err := scanSubscriptionCandidate(ps.db.QueryRowContext(ctx, query, candidate.CandidateID, candidate.UserID,
+		candidate.Merchant, candidate.ServiceName, candidate.Price, candidate.BillingPeriod, candidate.StartDate,
+		candidate.LastChargedAt, candidate.Occurrences))
+	if err != nil {
+		return err
+	}
+	*candidate = *saved
+	return nil
+}
+
+func (ps *SubcriptionDB) GetSubscriptionCandidateByID(ctx context.Context, candidateID uuid.UUID) (*domain.SubscriptionCandidate, error) {
+	defer metrics.ObserveQuery("GetSubscriptionCandidateByID")()
+	query := `SELECT ` + candidateColumns + ` FROM subscription_candidates WHERE id = $1`
+	c, err := scanSubscriptionCandidate(ps.db.QueryRowContext(ctx, query, candidateID))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound("subscription candidate not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (ps *SubcriptionDB) GetListOfSubscriptionCandidates(ctx context.Context, userID uuid.UUID,
+	status string) ([]domain.SubscriptionCandidate, error) {
+	defer metrics.ObserveQuery("GetListOfSubscriptionCandidates")()
+	builder := sq.Select(candidateColumns).
+		From("subscription_candidates").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("last_charged_at DESC", "merchant").
+		PlaceholderFormat(sq.Dollar)
+	if status != "" {
+		builder = builder.Where(sq.Eq{"status": status})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := ps.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []domain.SubscriptionCandidate
+	for rows.Next() {
+		c, err := scanSubscriptionCandidate(rows)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, *c)
+	}
+	return candidates, rows.Err()
+}
+
+func (ps *SubcriptionDB) ClaimSubscriptionCandidate(ctx context.Context, candidateID uuid.UUID,
+	status string) (*domain.SubscriptionCandidate, error) {
+	defer metrics.ObserveQuery("ClaimSubscriptionCandidate")()
+	query := `UPDATE subscription_candidates SET status = $1, updated_at = NOW()
+			  WHERE id = $2 AND status = 'pending'
+			  RETURNING ` + candidateColumns
+	c, err := scanSubscriptionCandidate(ps.db.QueryRowContext(ctx, query, status, candidateID))
+	if err == sql.ErrNoRows {
+		current, err := ps.GetSubscriptionCandidateByID(ctx, candidateID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrAlreadyExist(fmt.Sprintf("subscription candidate is already %s", current.Status))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (ps *SubcriptionDB) SetSubscriptionCandidateSubscription(ctx context.Context, candidateID,
+	subscriptionID uuid.UUID) error {
+	defer metrics.ObserveQuery("SetSubscriptionCandidateSubscription")()
+	query := `UPDATE subscription_candidates SET subscription_id = $1, updated_at = NOW() WHERE id = $2`
+	res, err := ps.db.ExecContext(ctx, query, subscriptionID, candidateID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound("subscription candidate not found")
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package postgres_storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+)
+
+// Pool returns the underlying connection pool, e.g. to export its statistics.
+func (ps *SubcriptionDB) Pool() *sql.DB {
+	return ps.pool
+}
+
+func (ps *SubcriptionDB) GetSubscriptionStats(ctx context.Context, at time.Time) (domain.SubscriptionStats, error) {
+	defer metrics.ObserveQuery("GetSubscriptionStats")()
+	monthStart := domain.MonthStart(at)
+	monthEnd := monthStart.AddDate(0, 1, -1)
+
+	query := `SELECT COUNT(*), COUNT(DISTINCT user_id),
+					 (SELECT COUNT(*) FROM outbox WHERE published_at IS NULL)
+			  FROM subscriptions
+			  WHERE deleted_at IS NULL AND start_date <= $1 AND (end_date IS NULL OR end_date >= $2)`
+	var stats domain.SubscriptionStats
+	err := ps.db.QueryRowContext(ctx, query, monthEnd, monthStart).Scan(&stats.ActiveSubscriptions, &stats.ActiveUsers,
+		&stats.PendingOutboxMessages)
+	if err != nil {
+		return domain.SubscriptionStats{}, err
+	}
+	return stats, nil
+}
@@ -1,58 +1,113 @@
 package postgres_storage
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 )
 
+// dbtx is the part of *sql.DB and *sql.Tx the queries rely on, so the same methods
+// work both on the pool and inside a transaction.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
 type SubcriptionDB struct {
-	db *sql.DB
+	db   dbtx
+	pool *sql.DB
+	// tx is set when the instance is bound to a transaction opened by InTransaction.
+	tx *sql.Tx
 }
 
-func NewSubscriptionDB(connStr string) (*SubcriptionDB, error) {
-	db, err := sql.Open("postgres", connStr)
+func NewSubscriptionDB(cfg Config) (*SubcriptionDB, error) {
+	db, err := sql.Open("postgres", cfg.ConnString())
 	if err != nil {
 		return nil, err
 	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
-	err = db.Ping()
-	if err != nil {
+	if err := connect(db, cfg); err != nil {
+		_ = db.Close()
 		return nil, err
 	}
 
-	return &SubcriptionDB{db: db}, nil
+	return &SubcriptionDB{
+		db:   retryDB{dbtx: tracedDB{db}, retries: cfg.ReadRetries, backoff: cfg.ReadRetryBackoff},
+		pool: db,
+	}, nil
 }
 
 func (ps *SubcriptionDB) Close() error {
-	if ps.db != nil {
-		return ps.db.Close()
+	if ps.pool != nil && ps.tx == nil {
+		return ps.pool.Close()
 	}
 	return nil
 }
 
-func (ps *SubcriptionDB) CreateSubscription(subs *domain.Subscription) error {
-	query := `INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date)
-			  VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := ps.db.Exec(query, subs.SubscriptionID, subs.ServiceName, subs.Price, subs.UserID, subs.StartDate, subs.EndDate)
-	if err != nil {
-		return err
-	}
-	return nil
+func (ps *SubcriptionDB) CreateSubscription(ctx context.Context, subs *domain.Subscription) error {
+	defer metrics.ObserveQuery("CreateSubscription")()
+	return ps.withTx(ctx, func(tx dbtx) error {
+		query := `INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date, billing_period)
+				  VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		_, err := tx.ExecContext(ctx, query, subs.SubscriptionID, subs.ServiceName, subs.Price, subs.UserID, subs.StartDate, subs.EndDate,
+			subs.BillingPeriod)
+		if err != nil {
+			return err
+		}
+		return recordChange(ctx, tx, domain.EventSubscriptionCreated, domain.EventActionCreated, nil, subs)
+	})
 }
 
-func (ps *SubcriptionDB) GetSubscriptionByID(subscriptionID uuid.UUID) (*domain.Subscription, error) {
-	query := `SELECT id, service_name, price, user_id, start_date, end_date FROM subscriptions WHERE id = $1`
+// ImportSubscriptions inserts all subscriptions in one transaction: either every row is
+// stored or none of them.
+func (ps *SubcriptionDB) ImportSubscriptions(ctx context.Context, subs []*domain.Subscription) error {
+	defer metrics.ObserveQuery("ImportSubscriptions")()
+	return ps.withTx(ctx, func(tx dbtx) error {
+		stmt, err := tx.PrepareContext(ctx, `INSERT INTO subscriptions (id, service_name, price, user_id, start_date, end_date, billing_period)
+								 VALUES ($1, $2, $3, $4, $5, $6, $7)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, sub := range subs {
+			_, err := stmt.ExecContext(ctx, sub.SubscriptionID, sub.ServiceName, sub.Price, sub.UserID, sub.StartDate, sub.EndDate,
+				sub.BillingPeriod)
+			if err != nil {
+				return err
+			}
+			if err := recordChange(ctx, tx, domain.EventSubscriptionCreated, domain.EventActionCreated, nil, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (ps *SubcriptionDB) GetSubscriptionByID(ctx context.Context, subscriptionID uuid.UUID) (*domain.Subscription, error) {
+	defer metrics.ObserveQuery("GetSubscriptionByID")()
+	query := `SELECT id, service_name, price, user_id, start_date, end_date, billing_period FROM subscriptions
+			  WHERE id = $1 AND deleted_at IS NULL`
 	var subs domain.Subscription
-	err := ps.db.QueryRow(query, subscriptionID).Scan(&subs.SubscriptionID,
+	err := ps.db.QueryRowContext(ctx, query, subscriptionID).Scan(&subs.SubscriptionID,
 		&subs.ServiceName,
 		&subs.Price,
 		&subs.UserID,
 		&subs.StartDate,
-		&subs.EndDate)
+		&subs.EndDate,
+		&subs.BillingPeriod)
 	if err == sql.ErrNoRows {
 		return nil, domain.ErrNotFound("subscription not found")
 	}
@@ -63,11 +118,15 @@ func (ps *SubcriptionDB) GetSubscriptionByID(subscriptionID uuid.UUID) (*domain.
 	return &subs, nil
 }
 
-func (ps *SubcriptionDB) GetListOfSubscriptions(filter *domain.SubscriptionFilter) ([]domain.Subscription, error) {
-	builder := sq.Select("id", "service_name", "price", "user_id", "start_date", "end_date").
+func listSubscriptionsQuery(filter *domain.SubscriptionFilter) sq.SelectBuilder {
+	builder := sq.Select("id", "service_name", "price", "user_id", "start_date", "end_date", "billing_period", "deleted_at").
 		From("subscriptions").
 		PlaceholderFormat(sq.Dollar)
 
+	if !filter.IncludeDeleted {
+		builder = builder.Where("deleted_at IS NULL")
+	}
+
 	if filter.UserID != nil {
 		builder = builder.Where(sq.Eq{"user_id": *filter.UserID})
 	}
@@ -83,13 +142,17 @@ func (ps *SubcriptionDB) GetListOfSubscriptions(filter *domain.SubscriptionFilte
 	if filter.EndDate != nil {
 		builder = builder.Where(sq.LtOrEq{"end_date": *filter.EndDate})
 	}
+	return builder
+}
 
-	query, args, err := builder.ToSql()
+func (ps *SubcriptionDB) GetListOfSubscriptions(ctx context.Context, filter *domain.SubscriptionFilter) ([]domain.Subscription, error) {
+	defer metrics.ObserveQuery("GetListOfSubscriptions")()
+	query, args, err := listSubscriptionsQuery(filter).ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := ps.db.Query(query, args...)
+	rows, err := ps.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -99,7 +162,7 @@ func (ps *SubcriptionDB) GetListOfSubscriptions(filter *domain.SubscriptionFilte
 	for rows.Next() {
 		var sub domain.Subscription
 		if err := rows.Scan(&sub.SubscriptionID, &sub.ServiceName, &sub.Price,
-			&sub.UserID, &sub.StartDate, &sub.EndDate); err != nil {
+			&sub.UserID, &sub.StartDate, &sub.EndDate, &sub.BillingPeriod, &sub.DeletedAt); err != nil {
 			return nil, err
 		}
 		result = append(result, sub)
@@ -111,13 +174,47 @@ func (ps *SubcriptionDB) GetListOfSubscriptions(filter *domain.SubscriptionFilte
 	return result, nil
 }
 
-func (ps *SubcriptionDB) GetTotalCost(filter *domain.TotalCostFilter) ([]domain.Subscription, error) {
-	builder := sq.Select("id", "service_name", "price", "user_id", "start_date", "end_date").
+// ExportSubscriptions reads the subscriptions matching the filter row by row from the cursor
+// and passes each of them to fn without collecting them in memory. An error returned by fn
+// stops the iteration.
+func (ps *SubcriptionDB) ExportSubscriptions(ctx context.Context, filter *domain.SubscriptionFilter,
+	fn func(domain.Subscription) error) error {
+	defer metrics.ObserveQuery("ExportSubscriptions")()
+	query, args, err := listSubscriptionsQuery(filter).OrderBy("start_date", "id").ToSql()
+	if err != nil {
+		return err
+	}
+
+	rows, err := ps.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sub domain.Subscription
+		if err := rows.Scan(&sub.SubscriptionID, &sub.ServiceName, &sub.Price,
+			&sub.UserID, &sub.StartDate, &sub.EndDate, &sub.BillingPeriod, &sub.DeletedAt); err != nil {
+			return err
+		}
+		if err := fn(sub); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (ps *SubcriptionDB) GetTotalCost(ctx context.Context, filter *domain.TotalCostFilter) ([]domain.Subscription, error) {
+	defer metrics.ObserveQuery("GetTotalCost")()
+	builder := sq.Select("id", "service_name", "price", "user_id", "start_date", "end_date", "billing_period").
 		From("subscriptions").
 		Where("start_date <= ?", filter.EndDate).
 		Where("(end_date IS NULL OR end_date >= ?)", filter.StartDate).
 		PlaceholderFormat(sq.Dollar)
 
+	if !filter.IncludeDeleted {
+		builder = builder.Where("deleted_at IS NULL")
+	}
 	if filter.UserID != nil {
 		builder = builder.Where(sq.Eq{"user_id": *filter.UserID})
 	}
@@ -130,7 +227,7 @@ func (ps *SubcriptionDB) GetTotalCost(filter *domain.TotalCostFilter) ([]domain.
 		return nil, err
 	}
 
-	rows, err := ps.db.Query(query, args...)
+	rows, err := ps.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +237,7 @@ func (ps *SubcriptionDB) GetTotalCost(filter *domain.TotalCostFilter) ([]domain.
 	for rows.Next() {
 		var s domain.Subscription
 		err = rows.Scan(&s.SubscriptionID, &s.ServiceName,
-			&s.Price, &s.UserID, &s.StartDate, &s.EndDate)
+			&s.Price, &s.UserID, &s.StartDate, &s.EndDate, &s.BillingPeriod)
 		if err != nil {
 			return nil, err
 		}
@@ -149,39 +246,176 @@ func (ps *SubcriptionDB) GetTotalCost(filter *domain.TotalCostFilter) ([]domain.
 	return subs, rows.Err()
 }
 
-func (ps *SubcriptionDB) PatchSubscriptionByID(subs *domain.Subscription) error {
-	if !ps.IsExist(subs.SubscriptionID) {
-		return domain.ErrNotFound("subscription not found")
+func (ps *SubcriptionDB) PatchSubscriptionByID(ctx context.Context, subs *domain.Subscription) error {
+	defer metrics.ObserveQuery("PatchSubscriptionByID")()
+	return ps.withTx(ctx, func(tx dbtx) error {
+		before, err := lockSubscription(ctx, tx, subs.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		query := `UPDATE subscriptions SET service_name = COALESCE($1, service_name),
+         				 price = COALESCE($2, price), end_date = COALESCE($3, end_date),
+					 billing_period = COALESCE(NULLIF($4, ''), billing_period)
+     					 WHERE id = $5
+					 RETURNING id, service_name, price, user_id, start_date, end_date, billing_period`
+		var patched domain.Subscription
+		err = tx.QueryRowContext(ctx, query, subs.ServiceName, subs.Price, subs.EndDate, subs.BillingPeriod, subs.SubscriptionID).Scan(
+			&patched.SubscriptionID, &patched.ServiceName, &patched.Price,
+			&patched.UserID, &patched.StartDate, &patched.EndDate, &patched.BillingPeriod)
+		if err != nil {
+			return err
+		}
+		return recordChange(ctx, tx, domain.EventSubscriptionUpdated, domain.EventActionUpdated, before, &patched)
+	})
+}
+
+func (ps *SubcriptionDB) ReplaceSubscription(ctx context.Context, subs *domain.Subscription) error {
+	defer metrics.ObserveQuery("ReplaceSubscription")()
+	return ps.withTx(ctx, func(tx dbtx) error {
+		before, err := lockSubscription(ctx, tx, subs.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		query := `UPDATE subscriptions SET service_name = $1, price = $2, user_id = $3,
+						 start_date = $4, end_date = $5, billing_period = $6
+						 WHERE id = $7`
+		_, err = tx.ExecContext(ctx, query, subs.ServiceName, subs.Price, subs.UserID, subs.StartDate, subs.EndDate, subs.BillingPeriod,
+			subs.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		return recordChange(ctx, tx, domain.EventSubscriptionUpdated, domain.EventActionReplaced, before, subs)
+	})
+}
+
+func (ps *SubcriptionDB) DeleteSubscriptionByID(ctx context.Context, subs *domain.Subscription) error {
+	defer metrics.ObserveQuery("DeleteSubscriptionByID")()
+	return ps.withTx(ctx, func(tx dbtx) error {
+		query := `UPDATE subscriptions SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL
+				  RETURNING service_name, price, user_id, start_date, end_date, billing_period, deleted_at`
+		err := tx.QueryRowContext(ctx, query, subs.SubscriptionID).Scan(&subs.ServiceName, &subs.Price, &subs.UserID,
+			&subs.StartDate, &subs.EndDate, &subs.BillingPeriod, &subs.DeletedAt)
+		if err == sql.ErrNoRows {
+			return domain.ErrNotFound("subscription not found")
+		}
+		if err != nil {
+			return err
+		}
+		before := *subs
+		before.DeletedAt = nil
+		return recordChange(ctx, tx, domain.EventSubscriptionDeleted, domain.EventActionDeleted, &before, subs)
+	})
+}
+
+func (ps *SubcriptionDB) RestoreSubscriptionByID(ctx context.Context, subs *domain.Subscription) error {
+	defer metrics.ObserveQuery("RestoreSubscriptionByID")()
+	return ps.withTx(ctx, func(tx dbtx) error {
+		before, err := lockDeletedSubscription(ctx, tx, subs.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		query := `UPDATE subscriptions SET deleted_at = NULL WHERE id = $1`
+		if _, err := tx.ExecContext(ctx, query, subs.SubscriptionID); err != nil {
+			return err
+		}
+		*subs = *before
+		subs.DeletedAt = nil
+		return recordChange(ctx, tx, domain.EventSubscriptionRestored, domain.EventActionRestored, before, subs)
+	})
+}
+
+// recordChange writes what follows from a change of a subscription in the transaction that
+// makes it: the lifecycle event for the outbox, the audit log entry and the deliveries of the
+// webhooks subscribed to it.
+func recordChange(ctx context.Context, tx dbtx, eventType, action string, before, after *domain.Subscription) error {
+	if err := insertOutboxMessage(ctx, tx, eventType, after); err != nil {
+		return err
 	}
-	query := `UPDATE subscriptions SET service_name = COALESCE($1, service_name),
-         			 price = COALESCE($2, price), end_date = COALESCE($3, end_date)
-     				 WHERE id = $4`
-	_, err := ps.db.Exec(query, subs.ServiceName, subs.Price, subs.EndDate, subs.SubscriptionID)
-	if err != nil {
+	if err := insertSubscriptionEvent(ctx, tx, action, before, after); err != nil {
 		return err
 	}
+	// Webhook payloads carry the previous state of updates only.
+	var previous *domain.Subscription
+	switch action {
+	case domain.EventActionUpdated, domain.EventActionReplaced,
+		domain.EventActionPriceChangeScheduled, domain.EventActionPriceChangeDeleted:
+		previous = before
+	}
+	for _, webhookEvent := range domain.WebhookEventsOfChange(action, before, after) {
+		if err := insertWebhookDeliveries(ctx, tx, webhookEvent, after, previous); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (ps *SubcriptionDB) DeleteSubscriptionByID(subs *domain.Subscription) error {
-	if !ps.IsExist(subs.SubscriptionID) {
-		return domain.ErrNotFound("subscription not found")
+// lockSubscription reads the subscription and locks its row until the transaction ends, so the
+// state recorded as before a change is the one the change was applied to.
+func lockSubscription(ctx context.Context, tx dbtx, subscriptionID uuid.UUID) (*domain.Subscription, error) {
+	query := `SELECT id, service_name, price, user_id, start_date, end_date, billing_period FROM subscriptions
+			  WHERE id = $1 AND deleted_at IS NULL
+			  FOR UPDATE`
+	var subs domain.Subscription
+	err := tx.QueryRowContext(ctx, query, subscriptionID).Scan(&subs.SubscriptionID, &subs.ServiceName, &subs.Price,
+		&subs.UserID, &subs.StartDate, &subs.EndDate, &subs.BillingPeriod)
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound("subscription not found")
 	}
-	query := `DELETE FROM subscriptions WHERE id = $1
-			  RETURNING service_name, price, user_id, start_date, end_date`
-	err := ps.db.QueryRow(query, subs.SubscriptionID).Scan(&subs.ServiceName, &subs.Price, &subs.UserID, &subs.StartDate, &subs.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	return &subs, nil
+}
+
+// lockDeletedSubscription is lockSubscription for a subscription in the trash; the returned
+// subscription has DeletedAt set.
+func lockDeletedSubscription(ctx context.Context, tx dbtx, subscriptionID uuid.UUID) (*domain.Subscription, error) {
+	query := `SELECT id, service_name, price, user_id, start_date, end_date, billing_period, deleted_at FROM subscriptions
+			  WHERE id = $1 AND deleted_at IS NOT NULL
+			  FOR UPDATE`
+	var subs domain.Subscription
+	err := tx.QueryRowContext(ctx, query, subscriptionID).Scan(&subs.SubscriptionID, &subs.ServiceName, &subs.Price,
+		&subs.UserID, &subs.StartDate, &subs.EndDate, &subs.BillingPeriod, &subs.DeletedAt)
 	if err == sql.ErrNoRows {
-		return domain.ErrNotFound("subscription not found")
+		return nil, domain.ErrNotFound("deleted subscription not found")
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	return &subs, nil
+}
+
+func (ps *SubcriptionDB) PurgeDeletedSubscriptions(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer metrics.ObserveQuery("PurgeDeletedSubscriptions")()
+	query := `DELETE FROM subscriptions WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+	res, err := ps.db.ExecContext(ctx, query, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
-func (ps *SubcriptionDB) IsExist(subscriptionID uuid.UUID) bool {
+func (ps *SubcriptionDB) IsExist(ctx context.Context, subscriptionID uuid.UUID) (bool, error) {
+	defer metrics.ObserveQuery("IsExist")()
 	var exists bool
-	query := `SELECT EXISTS(SELECT 1 FROM subscriptions WHERE id = $1)`
-	_ = ps.db.QueryRow(query, subscriptionID).Scan(&exists)
-	return exists
+	query := `SELECT EXISTS(SELECT 1 FROM subscriptions WHERE id = $1 AND deleted_at IS NULL)`
+	err := ps.db.QueryRowContext(ctx, query, subscriptionID).Scan(&exists)
+	return exists, err
+}
+
+// withTx runs fn in a transaction, committing only when fn succeeds. Inside InTransaction
+// fn joins the outer transaction instead.
+func (ps *SubcriptionDB) withTx(ctx context.Context, fn func(tx dbtx) error) error {
+	if ps.tx != nil {
+		return fn(ps.db)
+	}
+	tx, err := ps.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tracedDB{tx}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
 }
@@ -0,0 +1,103 @@
+package postgres_storage
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/reqctx"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+)
+
+// insertSubscriptionEvent appends a change to the audit log in the transaction that makes it,
+// so a change is never stored without its record.
+func insertSubscriptionEvent(ctx context.Context, tx dbtx, action string, before, after *domain.Subscription) error {
+	beforeJSON, err := snapshot(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := snapshot(after)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO subscription_events (id, subscription_id, action, actor, request_id, occurred_at, before, after)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err = tx.ExecContext(ctx, query, uuid.New(), after.SubscriptionID, action, reqctx.Actor(ctx), reqctx.RequestID(ctx),
+		time.Now().UTC(), nullJSON(beforeJSON), nullJSON(afterJSON))
+	return err
+}
+
+func (ps *SubcriptionDB) GetListOfSubscriptionEvents(ctx context.Context,
+	filter *domain.SubscriptionEventFilter) ([]domain.SubscriptionEvent, error) {
+	defer metrics.ObserveQuery("GetListOfSubscriptionEvents")()
+	builder := sq.Select("id", "subscription_id", "action", "actor", "request_id", "occurred_at", "before", "after").
+		From("subscription_events").
+		OrderBy("occurred_at", "id").
+		PlaceholderFormat(sq.Dollar)
+
+	if filter.SubscriptionID != nil {
+		builder = builder.Where(sq.Eq{"subscription_id": *filter.SubscriptionID})
+	}
+	if filter.Actor != nil {
+		builder = builder.Where(sq.Eq{"actor": *filter.Actor})
+	}
+	if filter.Action != nil {
+		builder = builder.Where(sq.Eq{"action": *filter.Action})
+	}
+	if filter.From != nil {
+		builder = builder.Where(sq.GtOrEq{"occurred_at": *filter.From})
+	}
+	if filter.To != nil {
+		builder = builder.Where(sq.LtOrEq{"occurred_at": *filter.To})
+	}
+	if filter.Limit > 0 {
+		builder = builder.Limit(uint64(filter.Limit))
+	}
+	if filter.Offset > 0 {
+		builder = builder.Offset(uint64(filter.Offset))
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := ps.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []domain.SubscriptionEvent
+	for rows.Next() {
+		var e domain.SubscriptionEvent
+		var before, after []byte
+		err = rows.Scan(&e.EventID, &e.SubscriptionID, &e.Action, &e.Actor,
+			&e.RequestID, &e.OccurredAt, &before, &after)
+		if err != nil {
+			return nil, err
+		}
+		e.Before, e.After = before, after
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func snapshot(subs *domain.Subscription) ([]byte, error) {
+	if subs == nil {
+		return nil, nil
+	}
+	return json.Marshal(subs)
+}
+
+// nullJSON stores an absent snapshot as SQL NULL instead of an empty jsonb value.
+func nullJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+	return string(raw)
+}
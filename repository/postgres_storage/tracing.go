@@ -0,0 +1,83 @@
+package postgres_storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/tracing"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedDB wraps the pool or a transaction and records a client span and a debug log line
+// for every statement. Only the query text is attached, never the arguments.
+type tracedDB struct {
+	dbtx
+}
+
+func (t tracedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	ctx, finish := startQuery(ctx, query)
+	res, err := t.dbtx.ExecContext(ctx, query, args...)
+	finish(err)
+	return res, err
+}
+
+func (t tracedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	ctx, finish := startQuery(ctx, query)
+	rows, err := t.dbtx.QueryContext(ctx, query, args...)
+	finish(err)
+	return rows, err
+}
+
+func (t tracedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	ctx, finish := startQuery(ctx, query)
+	row := t.dbtx.QueryRowContext(ctx, query, args...)
+	err := row.Err()
+	if errors.Is(err, sql.ErrNoRows) {
+		err = nil
+	}
+	finish(err)
+	return row
+}
+
+func (t tracedDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ctx, finish := startQuery(ctx, query)
+	stmt, err := t.dbtx.PrepareContext(ctx, query)
+	finish(err)
+	return stmt, err
+}
+
+// startQuery starts the span of a statement; the returned function ends it with the
+// statement's error.
+func startQuery(ctx context.Context, query string) (context.Context, func(error)) {
+	operation := queryOperation(query)
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "postgres "+operation, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemNamePostgreSQL,
+			semconv.DBOperationName(operation),
+			semconv.DBQueryText(query),
+		))
+	return ctx, func(err error) {
+		tracing.End(span, err)
+		attrs := []any{"layer", "repository", "operation", operation, "duration", time.Since(start)}
+		if err != nil {
+			attrs = append(attrs, "error", err)
+		}
+		slog.DebugContext(ctx, "query executed", attrs...)
+	}
+}
+
+// queryOperation returns the leading SQL keyword, e.g. SELECT or INSERT.
+func queryOperation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
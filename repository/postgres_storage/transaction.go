@@ -0,0 +1,43 @@
+package postgres_storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+
+	"github.com/lib/pq"
+)
+
+// InTransaction runs fn with a copy of the storage bound to a single transaction. The
+// transaction is committed when fn returns nil and rolled back otherwise.
+func (ps *SubcriptionDB) InTransaction(ctx context.Context, fn func(tx repository.TxDB) error) error {
+	if ps.tx != nil {
+		return fn(ps)
+	}
+	tx, err := ps.pool.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(&SubcriptionDB{db: tracedDB{tx}, pool: ps.pool, tx: tx}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func (ps *SubcriptionDB) Savepoint(ctx context.Context, name string) error {
+	if ps.tx == nil {
+		return fmt.Errorf("savepoint %s outside of a transaction", name)
+	}
+	_, err := ps.db.ExecContext(ctx, "SAVEPOINT "+pq.QuoteIdentifier(name))
+	return err
+}
+
+func (ps *SubcriptionDB) RollbackToSavepoint(ctx context.Context, name string) error {
+	if ps.tx == nil {
+		return fmt.Errorf("savepoint %s outside of a transaction", name)
+	}
+	_, err := ps.db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+pq.QuoteIdentifier(name))
+	return err
+}
@@ -0,0 +1,238 @@
+package postgres_storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func (ps *SubcriptionDB) CreateWebhook(ctx context.Context, webhook *domain.Webhook) error {
+	defer metrics.ObserveQuery("CreateWebhook")()
+	query := `INSERT INTO webhooks (id, url, secret, event_types, user_id, active, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := ps.db.ExecContext(ctx, query, webhook.WebhookID, webhook.URL, webhook.Secret, pq.Array(webhook.EventTypes),
+		webhook.UserID, webhook.Active, webhook.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ps *SubcriptionDB) GetWebhookByID(ctx context.Context, webhookID uuid.UUID) (*domain.Webhook, error) {
+	defer metrics.ObserveQuery("GetWebhookByID")()
+	query := `SELECT id, url, secret, event_types, user_id, active, created_at FROM webhooks WHERE id = $1`
+	webhook, err := scanWebhook(ps.db.QueryRowContext(ctx, query, webhookID))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound("webhook not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (ps *SubcriptionDB) GetListOfWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	defer metrics.ObserveQuery("GetListOfWebhooks")()
+	query := `SELECT id, url, secret, event_types, user_id, active, created_at FROM webhooks ORDER BY created_at`
+	return queryWebhooks(ctx, ps.db, query)
+}
+
+func (ps *SubcriptionDB) GetListOfWebhooksForEvent(ctx context.Context, eventType string, userID uuid.UUID) ([]domain.Webhook, error) {
+	defer metrics.ObserveQuery("GetListOfWebhooksForEvent")()
+	return queryWebhooks(ctx, ps.db, webhooksForEventQuery, eventType, userID)
+}
+
+const webhooksForEventQuery = `SELECT id, url, secret, event_types, user_id, active, created_at FROM webhooks
+			  WHERE active AND $1 = ANY(event_types) AND (user_id IS NULL OR user_id = $2)`
+
+func (ps *SubcriptionDB) PatchWebhookByID(ctx context.Context, webhook *domain.Webhook) error {
+	defer metrics.ObserveQuery("PatchWebhookByID")()
+	query := `UPDATE webhooks SET url = $1, event_types = $2, active = $3 WHERE id = $4`
+	res, err := ps.db.ExecContext(ctx, query, webhook.URL, pq.Array(webhook.EventTypes), webhook.Active, webhook.WebhookID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound("webhook not found")
+	}
+	return nil
+}
+
+func (ps *SubcriptionDB) DeleteWebhookByID(ctx context.Context, webhookID uuid.UUID) error {
+	defer metrics.ObserveQuery("DeleteWebhookByID")()
+	res, err := ps.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, webhookID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrNotFound("webhook not found")
+	}
+	return nil
+}
+
+func (ps *SubcriptionDB) CreateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	defer metrics.ObserveQuery("CreateWebhookDelivery")()
+	return insertWebhookDelivery(ctx, ps.db, delivery)
+}
+
+func insertWebhookDelivery(ctx context.Context, tx dbtx, delivery *domain.WebhookDelivery) error {
+	query := `INSERT INTO webhook_deliveries (id, webhook_id, event_type, payload, status, attempts, created_at, next_attempt_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := tx.ExecContext(ctx, query, delivery.DeliveryID, delivery.WebhookID, delivery.EventType, string(delivery.Payload),
+		delivery.Status, delivery.Attempts, delivery.CreatedAt, delivery.NextAttemptAt)
+	return err
+}
+
+// insertWebhookDeliveries queues a delivery of the event for every webhook subscribed to it, in
+// the transaction of the change that caused the event, so the deliveries exist exactly when the
+// change does.
+func insertWebhookDeliveries(ctx context.Context, tx dbtx, eventType string, subs, previous *domain.Subscription) error {
+	webhooks, err := queryWebhooks(ctx, tx, webhooksForEventQuery, eventType, subs.UserID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, webhook := range webhooks {
+		deliveryID := uuid.New()
+		payload, err := json.Marshal(domain.WebhookPayload{
+			DeliveryID:   deliveryID,
+			EventType:    eventType,
+			OccurredAt:   now,
+			Subscription: subs,
+			Previous:     previous,
+		})
+		if err != nil {
+			return err
+		}
+		err = insertWebhookDelivery(ctx, tx, &domain.WebhookDelivery{
+			DeliveryID:    deliveryID,
+			WebhookID:     webhook.WebhookID,
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        domain.WebhookDeliveryPending,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ps *SubcriptionDB) GetWebhookDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	defer metrics.ObserveQuery("GetWebhookDeliveryByID")()
+	query := `SELECT id, webhook_id, event_type, payload, status, attempts, response_code, last_error,
+			  created_at, next_attempt_at, delivered_at
+			  FROM webhook_deliveries WHERE id = $1`
+	delivery, err := scanWebhookDelivery(ps.db.QueryRowContext(ctx, query, deliveryID))
+	if err == sql.ErrNoRows {
+		return nil, domain.ErrNotFound("webhook delivery not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (ps *SubcriptionDB) GetListOfWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) ([]domain.WebhookDelivery, error) {
+	defer metrics.ObserveQuery("GetListOfWebhookDeliveries")()
+	query := `SELECT id, webhook_id, event_type, payload, status, attempts, response_code, last_error,
+			  created_at, next_attempt_at, delivered_at
+			  FROM webhook_deliveries WHERE webhook_id = $1 ORDER BY created_at DESC`
+	return ps.queryWebhookDeliveries(ctx, query, webhookID)
+}
+
+func (ps *SubcriptionDB) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	defer metrics.ObserveQuery("ClaimWebhookDeliveries")()
+	query := `UPDATE webhook_deliveries SET next_attempt_at = NOW() + $2 * INTERVAL '1 millisecond'
+			  WHERE id IN (
+				  SELECT id FROM webhook_deliveries
+				  WHERE status = 'pending' AND next_attempt_at <= NOW()
+				  ORDER BY created_at
+				  LIMIT $1
+				  FOR UPDATE SKIP LOCKED
+			  )
+			  RETURNING id, webhook_id, event_type, payload, status, attempts, response_code, last_error,
+			  created_at, next_attempt_at, delivered_at`
+	return ps.queryWebhookDeliveries(ctx, query, limit, lease.Milliseconds())
+}
+
+func (ps *SubcriptionDB) UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	defer metrics.ObserveQuery("UpdateWebhookDelivery")()
+	query := `UPDATE webhook_deliveries SET status = $1, attempts = $2, response_code = $3, last_error = $4,
+			  next_attempt_at = $5, delivered_at = $6
+			  WHERE id = $7`
+	_, err := ps.db.ExecContext(ctx, query, delivery.Status, delivery.Attempts, delivery.ResponseCode, delivery.LastError,
+		delivery.NextAttemptAt, delivery.DeliveredAt, delivery.DeliveryID)
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhook(row rowScanner) (*domain.Webhook, error) {
+	var w domain.Webhook
+	err := row.Scan(&w.WebhookID, &w.URL, &w.Secret, pq.Array(&w.EventTypes), &w.UserID, &w.Active, &w.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+func queryWebhooks(ctx context.Context, db dbtx, query string, args ...any) ([]domain.Webhook, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var webhooks []domain.Webhook
+	for rows.Next() {
+		w, err := scanWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, *w)
+	}
+	return webhooks, rows.Err()
+}
+
+func scanWebhookDelivery(row rowScanner) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	var payload []byte
+	err := row.Scan(&d.DeliveryID, &d.WebhookID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.ResponseCode, &d.LastError, &d.CreatedAt, &d.NextAttemptAt, &d.DeliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	return &d, nil
+}
+
+func (ps *SubcriptionDB) queryWebhookDeliveries(ctx context.Context, query string, args ...any) ([]domain.WebhookDelivery, error) {
+	rows, err := ps.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
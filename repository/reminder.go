@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+type ReminderDB interface {
+	// GetSubscriptionsEndingBetween returns subscriptions whose end_date lies in [from, to].
+	GetSubscriptionsEndingBetween(ctx context.Context, from, to time.Time) ([]domain.Subscription, error)
+	// GetOpenEndedSubscriptions returns subscriptions without end_date that started on or before startedBy.
+	GetOpenEndedSubscriptions(ctx context.Context, startedBy time.Time) ([]domain.Subscription, error)
+	// ClaimReminder records the reminder as sent through channel and reports false when it already was.
+	ClaimReminder(ctx context.Context, reminder *domain.Reminder, channel string) (bool, error)
+	// ReleaseReminder forgets a reminder claimed for channel so that it is sent there again.
+	ReleaseReminder(ctx context.Context, reminder *domain.Reminder, channel string) error
+}
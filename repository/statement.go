@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionCandidateDB interface {
+	// SaveSubscriptionCandidate stores a pending candidate, refreshing the pending one of the
+	// same user and merchant if there is one. The stored candidate is written back.
+	SaveSubscriptionCandidate(ctx context.Context, candidate *domain.SubscriptionCandidate) error
+	GetSubscriptionCandidateByID(ctx context.Context, candidateID uuid.UUID) (*domain.SubscriptionCandidate, error)
+	// GetListOfSubscriptionCandidates returns the candidates of the user, only those with the
+	// given status when it is not empty.
+	GetListOfSubscriptionCandidates(ctx context.Context, userID uuid.UUID, status string) ([]domain.SubscriptionCandidate, error)
+	// ClaimSubscriptionCandidate moves a pending candidate to the given status and returns it.
+	// A candidate that is no longer pending is an AlreadyExist error, so of two concurrent
+	// claims only one succeeds.
+	ClaimSubscriptionCandidate(ctx context.Context, candidateID uuid.UUID, status string) (*domain.SubscriptionCandidate, error)
+	// SetSubscriptionCandidateSubscription links the candidate to the subscription created from it.
+	SetSubscriptionCandidateSubscription(ctx context.Context, candidateID, subscriptionID uuid.UUID) error
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+type StatsDB interface {
+	// GetSubscriptionStats counts the subscriptions active in the month of at.
+	GetSubscriptionStats(ctx context.Context, at time.Time) (domain.SubscriptionStats, error)
+}
@@ -1,18 +1,32 @@
 package repository
 
 import (
+	"context"
+	"time"
+
 	"github.com/kasparovgs/subscription-aggregation-service/domain"
 
 	"github.com/google/uuid"
 )
 
 type SubscriptionDB interface {
-	CreateSubscription(subs *domain.Subscription) error
-	GetSubscriptionByID(subscriptionID uuid.UUID) (*domain.Subscription, error)
-	GetListOfSubscriptions(filter *domain.SubscriptionFilter) ([]domain.Subscription, error)
-	GetTotalCost(filter *domain.TotalCostFilter) ([]domain.Subscription, error)
-	PatchSubscriptionByID(subs *domain.Subscription) error
-	DeleteSubscriptionByID(subs *domain.Subscription) error
-	IsExist(subscriptionID uuid.UUID) bool
+	CreateSubscription(ctx context.Context, subs *domain.Subscription) error
+	ImportSubscriptions(ctx context.Context, subs []*domain.Subscription) error
+	GetSubscriptionByID(ctx context.Context, subscriptionID uuid.UUID) (*domain.Subscription, error)
+	GetListOfSubscriptions(ctx context.Context, filter *domain.SubscriptionFilter) ([]domain.Subscription, error)
+	// ExportSubscriptions calls fn for every subscription matching the filter as it is read.
+	ExportSubscriptions(ctx context.Context, filter *domain.SubscriptionFilter, fn func(domain.Subscription) error) error
+	GetTotalCost(ctx context.Context, filter *domain.TotalCostFilter) ([]domain.Subscription, error)
+	PatchSubscriptionByID(ctx context.Context, subs *domain.Subscription) error
+	ReplaceSubscription(ctx context.Context, subs *domain.Subscription) error
+	DeleteSubscriptionByID(ctx context.Context, subs *domain.Subscription) error
+	RestoreSubscriptionByID(ctx context.Context, subs *domain.Subscription) error
+	PurgeDeletedSubscriptions(ctx context.Context, deletedBefore time.Time) (int64, error)
+	IsExist(ctx context.Context, subscriptionID uuid.UUID) (bool, error)
+
+	CreatePriceChange(ctx context.Context, change *domain.PriceChange) error
+	// GetListOfPriceChanges returns the price changes of the subscriptions sorted by effective_from.
+	GetListOfPriceChanges(ctx context.Context, subscriptionIDs []uuid.UUID) ([]domain.PriceChange, error)
+	DeletePriceChangeByID(ctx context.Context, subscriptionID, priceChangeID uuid.UUID) error
 	Close() error
 }
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	"context"
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+type SubscriptionEventDB interface {
+	GetListOfSubscriptionEvents(ctx context.Context, filter *domain.SubscriptionEventFilter) ([]domain.SubscriptionEvent, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import "context"
+
+// TxDB gives access to the repositories within a single database transaction.
+type TxDB interface {
+	SubscriptionDB
+	SubscriptionEventDB
+	WebhookDB
+	BudgetDB
+	SubscriptionCandidateDB
+	// Savepoint marks a point the transaction can later be rolled back to without
+	// losing the work done before it.
+	Savepoint(ctx context.Context, name string) error
+	RollbackToSavepoint(ctx context.Context, name string) error
+}
+
+type Transactor interface {
+	InTransaction(ctx context.Context, fn func(tx TxDB) error) error
+}
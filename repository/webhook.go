@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+type WebhookDB interface {
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) error
+	GetWebhookByID(ctx context.Context, webhookID uuid.UUID) (*domain.Webhook, error)
+	GetListOfWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	// GetListOfWebhooksForEvent returns active webhooks subscribed to eventType for the given user.
+	GetListOfWebhooksForEvent(ctx context.Context, eventType string, userID uuid.UUID) ([]domain.Webhook, error)
+	PatchWebhookByID(ctx context.Context, webhook *domain.Webhook) error
+	DeleteWebhookByID(ctx context.Context, webhookID uuid.UUID) error
+
+	CreateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+	GetWebhookDeliveryByID(ctx context.Context, deliveryID uuid.UUID) (*domain.WebhookDelivery, error)
+	GetListOfWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) ([]domain.WebhookDelivery, error)
+	// ClaimWebhookDeliveries returns up to limit pending deliveries and hides them from other dispatchers for lease.
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error)
+	UpdateWebhookDelivery(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
@@ -0,0 +1,14 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+type Audit interface {
+	GetSubscriptionHistory(ctx context.Context, subscriptionID uuid.UUID) ([]domain.SubscriptionEvent, error)
+	GetListOfEvents(ctx context.Context, filter *domain.SubscriptionEventFilter) ([]domain.SubscriptionEvent, error)
+}
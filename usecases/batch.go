@@ -0,0 +1,11 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+type Batch interface {
+	ApplyBatch(ctx context.Context, ops []domain.BatchOperation, mode string) (*domain.BatchResult, error)
+}
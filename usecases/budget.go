@@ -0,0 +1,19 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+type Budget interface {
+	CreateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error)
+	GetBudgetByID(ctx context.Context, budgetID uuid.UUID) (*domain.Budget, error)
+	GetListOfBudgets(ctx context.Context, userID *uuid.UUID) ([]domain.Budget, error)
+	PatchBudgetByID(ctx context.Context, budget *domain.Budget) (*domain.Budget, error)
+	DeleteBudgetByID(ctx context.Context, budgetID uuid.UUID) error
+	GetBudgetStatus(ctx context.Context, userID uuid.UUID, month time.Time) ([]domain.BudgetStatus, error)
+}
@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+type Calendar interface {
+	// CreateCalendarToken issues a new feed token for the user; the previous one stops working.
+	CreateCalendarToken(ctx context.Context, userID uuid.UUID) (string, error)
+	RevokeCalendarToken(ctx context.Context, userID uuid.UUID) error
+	// GetCalendarEvents checks the token and returns the renewals and ends of the user's
+	// subscriptions within the next months.
+	GetCalendarEvents(ctx context.Context, userID uuid.UUID, token string, months int) ([]domain.CalendarEvent, error)
+}
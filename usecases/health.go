@@ -0,0 +1,15 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+type Health interface {
+	// Readiness checks the dependencies needed to serve requests.
+	Readiness(ctx context.Context) *domain.HealthReport
+	// StartDraining makes readiness fail from now on, so no new traffic is routed to
+	// the instance while it shuts down.
+	StartDraining()
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+
+	"github.com/google/uuid"
+)
+
+const maxAuditLimit = 1000
+
+type Audit struct {
+	eventRepo repository.SubscriptionEventDB
+}
+
+func NewAudit(eventRepo repository.SubscriptionEventDB) *Audit {
+	return &Audit{eventRepo: eventRepo}
+}
+
+func (a *Audit) GetSubscriptionHistory(ctx context.Context, subscriptionID uuid.UUID) ([]domain.SubscriptionEvent, error) {
+	events, err := a.eventRepo.GetListOfSubscriptionEvents(ctx, &domain.SubscriptionEventFilter{SubscriptionID: &subscriptionID})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get subscription history from repository",
+			"layer", "service",
+			"error", err,
+			"subscription_id", subscriptionID)
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, domain.ErrNotFound("no history for subscription")
+	}
+	slog.InfoContext(ctx, "subscription history received from repo",
+		"layer", "service",
+		"subscription_id", subscriptionID,
+		"events", len(events))
+	return events, nil
+}
+
+func (a *Audit) GetListOfEvents(ctx context.Context, filter *domain.SubscriptionEventFilter) ([]domain.SubscriptionEvent, error) {
+	if filter == nil {
+		slog.ErrorContext(ctx, "failed to get audit events by nil filter")
+		return nil, domain.ErrBadRequest("failed to get audit events by nil filter")
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		slog.ErrorContext(ctx, "from cannot be after to", "layer", "service")
+		return nil, domain.ErrBadRequest("from cannot be after to")
+	}
+	if filter.Limit <= 0 || filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+	events, err := a.eventRepo.GetListOfSubscriptionEvents(ctx, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get audit events by filter", "layer", "service", "error", err)
+		return nil, err
+	}
+	slog.InfoContext(ctx, "audit events by filter successfully found", "layer", "service", "events", len(events))
+	return events, nil
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+)
+
+const maxBatchOperations = 1000
+
+// errBatchAborted rolls back an atomic batch after one of its operations failed.
+var errBatchAborted = errors.New("batch aborted")
+
+// Batch applies lists of subscription changes in one transaction using the regular
+// Subcription service bound to that transaction, so every operation gets the same
+// validation, audit records and events as its single-request counterpart.
+type Batch struct {
+	transactor repository.Transactor
+}
+
+func NewBatch(transactor repository.Transactor) *Batch {
+	return &Batch{transactor: transactor}
+}
+
+func (b *Batch) ApplyBatch(ctx context.Context, ops []domain.BatchOperation, mode string) (*domain.BatchResult, error) {
+	if len(ops) == 0 {
+		slog.ErrorContext(ctx, "empty batch", "layer", "service")
+		return nil, domain.ErrBadRequest("batch has no operations")
+	}
+	if len(ops) > maxBatchOperations {
+		slog.ErrorContext(ctx, "batch is too large", "layer", "service", "operations", len(ops))
+		return nil, domain.ErrBadRequest(fmt.Sprintf("batch cannot have more than %d operations", maxBatchOperations))
+	}
+	if !domain.IsBatchMode(mode) {
+		slog.ErrorContext(ctx, "unknown batch mode", "layer", "service", "mode", mode)
+		return nil, domain.ErrBadRequest(fmt.Sprintf("unknown batch mode: %q", mode))
+	}
+
+	result := &domain.BatchResult{Mode: mode, Results: make([]domain.BatchOperationResult, len(ops))}
+	for i, op := range ops {
+		result.Results[i] = domain.BatchOperationResult{Index: i, Op: op.Op, Status: domain.BatchStatusSkipped}
+	}
+
+	err := b.transactor.InTransaction(ctx, func(tx repository.TxDB) error {
+		subs := NewSubscription(tx, tx)
+		for i, op := range ops {
+			res := &result.Results[i]
+			savepoint := fmt.Sprintf("batch_op_%d", i)
+			if mode == domain.BatchModeBestEffort {
+				if err := tx.Savepoint(ctx, savepoint); err != nil {
+					return err
+				}
+			}
+
+			sub, err := applyBatchOperation(ctx, subs, op)
+			if err == nil {
+				res.Status = domain.BatchStatusOK
+				res.Subscription = sub
+				continue
+			}
+			res.Status = domain.BatchStatusFailed
+			res.Code, res.Error = batchError(err)
+
+			if mode == domain.BatchModeAtomic {
+				return errBatchAborted
+			}
+			if err := tx.RollbackToSavepoint(ctx, savepoint); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, errBatchAborted) {
+		for i := range result.Results {
+			if result.Results[i].Status == domain.BatchStatusOK {
+				result.Results[i].Status = domain.BatchStatusRolledBack
+				result.Results[i].Subscription = nil
+			}
+		}
+		slog.InfoContext(ctx, "batch rolled back", "layer", "service", "operations", len(ops))
+		return result, nil
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to apply batch", "layer", "service", "operations", len(ops), "error", err)
+		return nil, err
+	}
+
+	result.Committed = true
+	slog.InfoContext(ctx, "batch applied", "layer", "service", "operations", len(ops), "mode", mode)
+	return result, nil
+}
+
+func applyBatchOperation(ctx context.Context, subs *Subcription, op domain.BatchOperation) (*domain.Subscription, error) {
+	if op.Subscription == nil {
+		return nil, domain.ErrBadRequest("operation has no subscription")
+	}
+	switch op.Op {
+	case domain.BatchOpCreate:
+		if _, err := subs.CreateSubscription(ctx, op.Subscription); err != nil {
+			return nil, err
+		}
+		return op.Subscription, nil
+	case domain.BatchOpPatch:
+		return subs.PatchSubscriptionByID(ctx, op.Subscription)
+	case domain.BatchOpDelete:
+		return subs.DeleteSubscriptionByID(ctx, op.Subscription)
+	default:
+		return nil, domain.ErrBadRequest(fmt.Sprintf("unknown operation: %q", op.Op))
+	}
+}
+
+func batchError(err error) (int, string) {
+	var myErr *domain.MyErr
+	if errors.As(err, &myErr) {
+		return myErr.Code, myErr.Message
+	}
+	return http.StatusInternalServerError, err.Error()
+}
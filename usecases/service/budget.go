@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+
+	"github.com/google/uuid"
+)
+
+type Budget struct {
+	budgetRepo       repository.BudgetDB
+	subscriptionRepo repository.SubscriptionDB
+}
+
+func NewBudget(budgetRepo repository.BudgetDB, subsRepo repository.SubscriptionDB) *Budget {
+	return &Budget{budgetRepo: budgetRepo, subscriptionRepo: subsRepo}
+}
+
+func (b *Budget) CreateBudget(ctx context.Context, budget *domain.Budget) (*domain.Budget, error) {
+	budget.BudgetID = uuid.New()
+	budget.CreatedAt = time.Now().UTC()
+	if err := b.budgetRepo.CreateBudget(ctx, budget); err != nil {
+		slog.ErrorContext(ctx, "failed to create budget in repository", "layer", "service", "error", err, "user_id", budget.UserID)
+		return nil, err
+	}
+	slog.InfoContext(ctx, "budget created", "layer", "service", "budget_id", budget.BudgetID, "user_id", budget.UserID)
+	evaluateBudgets(ctx, b.budgetRepo, b.subscriptionRepo, budget.UserID, time.Now())
+	return budget, nil
+}
+
+func (b *Budget) GetBudgetByID(ctx context.Context, budgetID uuid.UUID) (*domain.Budget, error) {
+	budget, err := b.budgetRepo.GetBudgetByID(ctx, budgetID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get budget from repository", "layer", "service", "error", err, "budget_id", budgetID)
+		return nil, err
+	}
+	return budget, nil
+}
+
+func (b *Budget) GetListOfBudgets(ctx context.Context, userID *uuid.UUID) ([]domain.Budget, error) {
+	budgets, err := b.budgetRepo.GetListOfBudgets(ctx, userID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get list of budgets", "layer", "service", "error", err)
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func (b *Budget) PatchBudgetByID(ctx context.Context, budget *domain.Budget) (*domain.Budget, error) {
+	if err := b.budgetRepo.PatchBudgetByID(ctx, budget); err != nil {
+		slog.ErrorContext(ctx, "failed to patch budget in repository", "layer", "service", "error", err, "budget_id", budget.BudgetID)
+		return nil, err
+	}
+	patched, err := b.budgetRepo.GetBudgetByID(ctx, budget.BudgetID)
+	if err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "budget patched", "layer", "service", "budget_id", patched.BudgetID)
+	evaluateBudgets(ctx, b.budgetRepo, b.subscriptionRepo, patched.UserID, time.Now())
+	return patched, nil
+}
+
+func (b *Budget) DeleteBudgetByID(ctx context.Context, budgetID uuid.UUID) error {
+	if err := b.budgetRepo.DeleteBudgetByID(ctx, budgetID); err != nil {
+		slog.ErrorContext(ctx, "failed to delete budget from repository", "layer", "service", "error", err, "budget_id", budgetID)
+		return err
+	}
+	slog.InfoContext(ctx, "budget deleted", "layer", "service", "budget_id", budgetID)
+	return nil
+}
+
+func (b *Budget) GetBudgetStatus(ctx context.Context, userID uuid.UUID, month time.Time) ([]domain.BudgetStatus, error) {
+	month = domain.MonthStart(month)
+	statuses, err := budgetStatuses(ctx, b.budgetRepo, b.subscriptionRepo, userID, month)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get budget status", "layer", "service", "error", err, "user_id", userID)
+		return nil, err
+	}
+
+	alerts, err := b.budgetRepo.GetListOfBudgetAlerts(ctx, userID, month)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get budget alerts", "layer", "service", "error", err, "user_id", userID)
+		return nil, err
+	}
+	for i := range alerts {
+		for j := range statuses {
+			if statuses[j].Budget.BudgetID == alerts[i].BudgetID {
+				statuses[j].Alert = &alerts[i]
+			}
+		}
+	}
+	slog.InfoContext(ctx, "budget status calculated", "layer", "service", "user_id", userID, "budgets", len(statuses))
+	return statuses, nil
+}
+
+// budgetStatuses computes the spend of every budget of the user with the total cost logic.
+func budgetStatuses(ctx context.Context, budgetRepo repository.BudgetDB, subscriptionRepo repository.SubscriptionDB,
+	userID uuid.UUID, month time.Time) ([]domain.BudgetStatus, error) {
+	budgets, err := budgetRepo.GetListOfBudgets(ctx, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]domain.BudgetStatus, 0, len(budgets))
+	for _, budget := range budgets {
+		spent, err := totalCost(ctx, subscriptionRepo, &domain.TotalCostFilter{
+			UserID:      &budget.UserID,
+			ServiceName: budget.ServiceName,
+			StartDate:   month,
+			EndDate:     month,
+		})
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, domain.BudgetStatus{
+			Budget:    budget,
+			Month:     month,
+			Spent:     spent,
+			Remaining: budget.MonthlyLimit - spent,
+			Exceeded:  spent > budget.MonthlyLimit,
+		})
+	}
+	return statuses, nil
+}
+
+// evaluateBudgets records an alert for every budget of the user exceeded in the month of at.
+// A failure is logged and does not undo the change that triggered the evaluation.
+func evaluateBudgets(ctx context.Context, budgetRepo repository.BudgetDB, subscriptionRepo repository.SubscriptionDB,
+	userID uuid.UUID, at time.Time) {
+	ctx = context.WithoutCancel(ctx)
+	month := domain.MonthStart(at)
+	statuses, err := budgetStatuses(ctx, budgetRepo, subscriptionRepo, userID, month)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to evaluate budgets", "layer", "service", "error", err, "user_id", userID)
+		return
+	}
+
+	for _, status := range statuses {
+		if !status.Exceeded {
+			continue
+		}
+		alert := &domain.BudgetAlert{
+			AlertID:      uuid.New(),
+			BudgetID:     status.Budget.BudgetID,
+			Month:        month,
+			MonthlyLimit: status.Budget.MonthlyLimit,
+			Spent:        status.Spent,
+			CreatedAt:    time.Now().UTC(),
+		}
+		created, err := budgetRepo.CreateBudgetAlert(ctx, alert)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to record budget alert", "layer", "service", "error", err, "budget_id", alert.BudgetID)
+			continue
+		}
+		if created {
+			slog.WarnContext(ctx, "budget exceeded",
+				"layer", "service",
+				"budget_id", alert.BudgetID,
+				"user_id", userID,
+				"month", month.Format("01-2006"),
+				"monthly_limit", alert.MonthlyLimit,
+				"spent", alert.Spent)
+		}
+	}
+}
+
+// BudgetEvaluator periodically evaluates every budget for the current month,
+// catching overspend caused by time passing rather than by a change.
+type BudgetEvaluator struct {
+	budgetRepo       repository.BudgetDB
+	subscriptionRepo repository.SubscriptionDB
+	interval         time.Duration
+}
+
+func NewBudgetEvaluator(budgetRepo repository.BudgetDB, subsRepo repository.SubscriptionDB, interval time.Duration) *BudgetEvaluator {
+	return &BudgetEvaluator{budgetRepo: budgetRepo, subscriptionRepo: subsRepo, interval: interval}
+}
+
+// Run evaluates on every tick until ctx is cancelled.
+func (e *BudgetEvaluator) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.evaluate(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "budget evaluator stopped", "layer", "service")
+			return
+		case <-ticker.C:
+			e.evaluate(ctx)
+		}
+	}
+}
+
+func (e *BudgetEvaluator) evaluate(ctx context.Context) {
+	budgets, err := e.budgetRepo.GetListOfBudgets(ctx, nil)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get budgets for evaluation", "layer", "service", "error", err)
+		return
+	}
+
+	seen := make(map[uuid.UUID]struct{})
+	now := time.Now()
+	for _, budget := range budgets {
+		if _, ok := seen[budget.UserID]; ok {
+			continue
+		}
+		seen[budget.UserID] = struct{}{}
+		evaluateBudgets(ctx, e.budgetRepo, e.subscriptionRepo, budget.UserID, now)
+	}
+	slog.InfoContext(ctx, "budgets evaluated", "layer", "service", "users", len(seen))
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+
+	"github.com/google/uuid"
+)
+
+const maxCalendarMonths = 36
+
+type Calendar struct {
+	tokenRepo        repository.CalendarTokenDB
+	subscriptionRepo repository.SubscriptionDB
+}
+
+func NewCalendar(tokenRepo repository.CalendarTokenDB, subsRepo repository.SubscriptionDB) *Calendar {
+	return &Calendar{tokenRepo: tokenRepo, subscriptionRepo: subsRepo}
+}
+
+func (c *Calendar) CreateCalendarToken(ctx context.Context, userID uuid.UUID) (string, error) {
+	token, err := newWebhookSecret()
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to generate calendar token", "layer", "service", "error", err)
+		return "", err
+	}
+	err = c.tokenRepo.SaveCalendarToken(ctx, &domain.CalendarToken{
+		UserID:    userID,
+		TokenHash: hashCalendarToken(token),
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to save calendar token", "layer", "service", "user_id", userID, "error", err)
+		return "", err
+	}
+	slog.InfoContext(ctx, "calendar token created", "layer", "service", "user_id", userID)
+	return token, nil
+}
+
+func (c *Calendar) RevokeCalendarToken(ctx context.Context, userID uuid.UUID) error {
+	if err := c.tokenRepo.DeleteCalendarToken(ctx, userID); err != nil {
+		slog.ErrorContext(ctx, "failed to revoke calendar token", "layer", "service", "user_id", userID, "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "calendar token revoked", "layer", "service", "user_id", userID)
+	return nil
+}
+
+func (c *Calendar) GetCalendarEvents(ctx context.Context, userID uuid.UUID, token string,
+	months int) ([]domain.CalendarEvent, error) {
+	if months < 1 || months > maxCalendarMonths {
+		return nil, domain.ErrBadRequest(fmt.Sprintf("months must be between 1 and %d", maxCalendarMonths))
+	}
+	stored, err := c.tokenRepo.GetCalendarToken(ctx, userID)
+	var myErr *domain.MyErr
+	if err != nil && !(errors.As(err, &myErr) && myErr.Code == domain.CodeNotFound) {
+		slog.ErrorContext(ctx, "failed to get calendar token", "layer", "service", "user_id", userID, "error", err)
+		return nil, err
+	}
+	if stored == nil || subtle.ConstantTimeCompare([]byte(stored.TokenHash), []byte(hashCalendarToken(token))) != 1 {
+		slog.WarnContext(ctx, "invalid calendar token", "layer", "service", "user_id", userID)
+		return nil, domain.ErrUnauthorized("invalid calendar token")
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	from := domain.MonthStart(today)
+	to := from.AddDate(0, months, 0).AddDate(0, 0, -1)
+	subs, err := c.subscriptionRepo.GetTotalCost(ctx, &domain.TotalCostFilter{UserID: &userID, StartDate: from, EndDate: to})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get subscriptions for calendar", "layer", "service", "user_id", userID, "error", err)
+		return nil, err
+	}
+	changes, err := priceChangesBySubscription(ctx, c.subscriptionRepo, subs)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get price changes for calendar", "layer", "service", "user_id", userID, "error", err)
+		return nil, err
+	}
+
+	var events []domain.CalendarEvent
+	for _, sub := range subs {
+		for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+			if month.Before(today) {
+				continue
+			}
+			if amount := sub.ChargeForMonth(month, changes[sub.SubscriptionID]); amount > 0 {
+				events = append(events, domain.CalendarEvent{
+					Subscription: sub,
+					Kind:         domain.ReminderKindRenewal,
+					Date:         month,
+					Amount:       amount,
+				})
+			}
+		}
+		if sub.EndDate != nil {
+			last := domain.LastActiveDay(*sub.EndDate)
+			if !last.Before(today) && !last.After(to) {
+				events = append(events, domain.CalendarEvent{Subscription: sub, Kind: domain.ReminderKindExpiry, Date: last})
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+
+	slog.InfoContext(ctx, "calendar events built", "layer", "service", "user_id", userID, "events", len(events))
+	return events, nil
+}
+
+func hashCalendarToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
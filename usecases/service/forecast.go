@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/tracing"
+
+	"github.com/google/uuid"
+)
+
+const maxForecastMonths = 120
+
+// GetForecast projects the spend of every month from filter.From on, leaving out the excluded
+// subscriptions to answer "what if I cancel them".
+func (s *Subcription) GetForecast(ctx context.Context, filter *domain.ForecastFilter) ([]domain.ForecastMonth, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.GetForecast")
+	defer span.End()
+
+	if filter == nil {
+		slog.ErrorContext(ctx, "failed to get forecast by nil filter")
+		return nil, domain.ErrBadRequest("failed to get forecast by nil filter")
+	}
+	if filter.Months < 1 || filter.Months > maxForecastMonths {
+		return nil, domain.ErrBadRequest("months must be between 1 and 120")
+	}
+
+	from := domain.MonthStart(filter.From)
+	to := from.AddDate(0, filter.Months-1, 0)
+	subs, err := s.subscriptionRepo.GetTotalCost(ctx, &domain.TotalCostFilter{
+		UserID:      filter.UserID,
+		ServiceName: filter.ServiceName,
+		StartDate:   from,
+		EndDate:     to,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get subscriptions for forecast", "layer", "service", "error", err)
+		return nil, err
+	}
+
+	excluded := make(map[uuid.UUID]struct{}, len(filter.Exclude))
+	for _, id := range filter.Exclude {
+		excluded[id] = struct{}{}
+	}
+	active := subs[:0]
+	for _, sub := range subs {
+		if _, ok := excluded[sub.SubscriptionID]; !ok {
+			active = append(active, sub)
+		}
+	}
+
+	changes, err := priceChangesBySubscription(ctx, s.subscriptionRepo, active)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get price changes for forecast", "layer", "service", "error", err)
+		return nil, err
+	}
+
+	forecast := make([]domain.ForecastMonth, 0, filter.Months)
+	for month := from; !month.After(to); month = month.AddDate(0, 1, 0) {
+		fm := domain.ForecastMonth{Month: month, Items: []domain.ForecastItem{}}
+		for _, sub := range active {
+			amount := sub.ChargeForMonth(month, changes[sub.SubscriptionID])
+			if amount == 0 {
+				continue
+			}
+			fm.Total += amount
+			fm.Items = append(fm.Items, domain.ForecastItem{
+				SubscriptionID: sub.SubscriptionID,
+				ServiceName:    sub.ServiceName,
+				Amount:         amount,
+			})
+		}
+		forecast = append(forecast, fm)
+	}
+	slog.InfoContext(ctx, "forecast calculated",
+		"layer", "service",
+		"months", filter.Months,
+		"subscriptions", len(active),
+		"excluded", len(filter.Exclude))
+	return forecast, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+)
+
+type Health struct {
+	healthRepo repository.HealthDB
+	timeout    time.Duration
+	// schemaVersion is the newest migration the binary knows; an older schema is not ready.
+	schemaVersion uint
+	draining      atomic.Bool
+}
+
+func NewHealth(healthRepo repository.HealthDB, timeout time.Duration, schemaVersion uint) *Health {
+	return &Health{healthRepo: healthRepo, timeout: timeout, schemaVersion: schemaVersion}
+}
+
+func (h *Health) StartDraining() {
+	h.draining.Store(true)
+	slog.Info("draining, readiness checks fail from now on", "layer", "service")
+}
+
+func (h *Health) Readiness(ctx context.Context) *domain.HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	report := &domain.HealthReport{Status: domain.HealthStatusOK, Draining: h.draining.Load()}
+	if report.Draining {
+		report.Status = domain.HealthStatusUnavailable
+	}
+
+	database := domain.HealthCheck{Name: "database", Status: domain.HealthStatusOK}
+	if err := h.healthRepo.Ping(ctx); err != nil {
+		database.Status = domain.HealthStatusUnavailable
+		database.Error = err.Error()
+	}
+	report.Checks = append(report.Checks, database)
+
+	migrations := domain.HealthCheck{Name: "migrations", Status: domain.HealthStatusOK}
+	version, dirty, err := h.healthRepo.GetSchemaVersion(ctx)
+	switch {
+	case err != nil:
+		migrations.Status = domain.HealthStatusUnavailable
+		migrations.Error = err.Error()
+	case version == 0:
+		migrations.Status = domain.HealthStatusUnavailable
+		migrations.Error = "no migrations applied"
+	case dirty:
+		migrations.Status = domain.HealthStatusUnavailable
+		migrations.Error = fmt.Sprintf("migration %d failed and left the schema dirty", version)
+	case version < h.schemaVersion:
+		migrations.Status = domain.HealthStatusUnavailable
+		migrations.Error = fmt.Sprintf("schema version %d is behind %d", version, h.schemaVersion)
+	}
+	report.SchemaVersion = version
+	report.Checks = append(report.Checks, migrations)
+
+	for _, check := range report.Checks {
+		if check.Status != domain.HealthStatusOK {
+			report.Status = domain.HealthStatusUnavailable
+			slog.WarnContext(ctx, "readiness check failed",
+				"layer", "service",
+				"check", check.Name,
+				"error", check.Error)
+		}
+	}
+	return report
+}
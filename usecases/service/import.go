@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/tracing"
+
+	"github.com/google/uuid"
+)
+
+const maxImportRows = 10000
+
+func (s *Subcription) ImportSubscriptions(ctx context.Context, subs []*domain.Subscription, dryRun bool) ([]uuid.UUID, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.ImportSubscriptions")
+	defer span.End()
+
+	if len(subs) == 0 {
+		slog.ErrorContext(ctx, "nothing to import", "layer", "service")
+		return nil, domain.ErrBadRequest("nothing to import")
+	}
+	if len(subs) > maxImportRows {
+		slog.ErrorContext(ctx, "too many rows to import", "layer", "service", "rows", len(subs))
+		return nil, domain.ErrBadRequest(fmt.Sprintf("cannot import more than %d subscriptions at once", maxImportRows))
+	}
+	if dryRun {
+		slog.InfoContext(ctx, "subscriptions import checked", "layer", "service", "rows", len(subs))
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(subs))
+	for _, sub := range subs {
+		sub.SubscriptionID = uuid.New()
+		ids = append(ids, sub.SubscriptionID)
+	}
+	if err := s.subscriptionRepo.ImportSubscriptions(ctx, subs); err != nil {
+		slog.ErrorContext(ctx, "failed to import subscriptions in repository", "layer", "service", "rows", len(subs), "error", err)
+		return nil, err
+	}
+
+	users := make(map[uuid.UUID]struct{})
+	for _, sub := range subs {
+		users[sub.UserID] = struct{}{}
+	}
+	for userID := range users {
+		evaluateBudgets(ctx, s.budgetRepo, s.subscriptionRepo, userID, time.Now())
+	}
+
+	slog.InfoContext(ctx, "subscriptions imported", "layer", "service", "rows", len(subs))
+	return ids, nil
+}
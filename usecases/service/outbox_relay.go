@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/publisher"
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+)
+
+// OutboxRelay publishes pending outbox messages. A message is marked published only after
+// the publisher accepts it, so a crash in between leads to a redelivery, never to a loss.
+type OutboxRelay struct {
+	outboxRepo repository.OutboxDB
+	publisher  publisher.Publisher
+	interval   time.Duration
+	batchSize  int
+	lease      time.Duration
+	maxBackoff time.Duration
+}
+
+func NewOutboxRelay(outboxRepo repository.OutboxDB, pub publisher.Publisher,
+	interval time.Duration, batchSize int, lease, maxBackoff time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		publisher:  pub,
+		interval:   interval,
+		batchSize:  batchSize,
+		lease:      lease,
+		maxBackoff: maxBackoff,
+	}
+}
+
+// Run relays on every tick until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "outbox relay stopped", "layer", "service")
+			return
+		case <-ticker.C:
+			r.relay(ctx)
+		}
+	}
+}
+
+func (r *OutboxRelay) relay(ctx context.Context) {
+	messages, err := r.outboxRepo.ClaimOutboxMessages(ctx, r.batchSize, r.lease)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to claim outbox messages", "layer", "service", "error", err)
+		return
+	}
+
+	for _, msg := range messages {
+		if err := r.publisher.Publish(ctx, msg); err != nil {
+			nextAttemptAt := time.Now().Add(backoff(msg.Attempts, r.interval, r.maxBackoff))
+			slog.WarnContext(ctx, "failed to publish outbox message",
+				"layer", "service",
+				"error", err,
+				"message_id", msg.MessageID,
+				"event_type", msg.EventType,
+				"attempts", msg.Attempts,
+				"next_attempt_at", nextAttemptAt)
+			if err := r.outboxRepo.MarkOutboxMessageFailed(ctx, msg.MessageID, err.Error(), nextAttemptAt); err != nil {
+				slog.ErrorContext(ctx, "failed to mark outbox message failed", "layer", "service", "error", err, "message_id", msg.MessageID)
+			}
+			continue
+		}
+		if err := r.outboxRepo.MarkOutboxMessagePublished(ctx, msg.MessageID); err != nil {
+			slog.ErrorContext(ctx, "failed to mark outbox message published", "layer", "service", "error", err, "message_id", msg.MessageID)
+			continue
+		}
+		slog.DebugContext(ctx, "outbox message published",
+			"layer", "service",
+			"message_id", msg.MessageID,
+			"event_type", msg.EventType)
+	}
+}
+
+// backoff doubles base for every previous attempt, capped at max.
+func backoff(attempts int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempts && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/tracing"
+
+	"github.com/google/uuid"
+)
+
+func (s *Subcription) SchedulePriceChange(ctx context.Context, change *domain.PriceChange) (*domain.PriceChange, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.SchedulePriceChange")
+	defer span.End()
+
+	subs, err := s.subscriptionRepo.GetSubscriptionByID(ctx, change.SubscriptionID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get subscription for price change", "layer", "service", "error", err,
+			"subscription_id", change.SubscriptionID)
+		return nil, err
+	}
+	change.EffectiveFrom = domain.MonthStart(change.EffectiveFrom)
+	if change.EffectiveFrom.Before(domain.MonthStart(subs.StartDate)) {
+		return nil, domain.ErrBadRequest("price change cannot take effect before the subscription starts")
+	}
+	if subs.EndDate != nil && change.EffectiveFrom.After(*subs.EndDate) {
+		return nil, domain.ErrBadRequest("price change cannot take effect after the subscription ends")
+	}
+
+	change.PriceChangeID = uuid.New()
+	change.CreatedAt = time.Now().UTC()
+	if err := s.subscriptionRepo.CreatePriceChange(ctx, change); err != nil {
+		slog.ErrorContext(ctx, "failed to create price change in repository", "layer", "service", "error", err,
+			"subscription_id", change.SubscriptionID)
+		return nil, err
+	}
+	slog.InfoContext(ctx, "price change scheduled",
+		"layer", "service",
+		"subscription_id", change.SubscriptionID,
+		"effective_from", change.EffectiveFrom.Format("01-2006"),
+		"price", change.Price)
+	return change, nil
+}
+
+func (s *Subcription) GetListOfPriceChanges(ctx context.Context, subscriptionID uuid.UUID) ([]domain.PriceChange, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.GetListOfPriceChanges")
+	defer span.End()
+
+	exists, err := s.subscriptionRepo.IsExist(ctx, subscriptionID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to check subscription in repository", "layer", "service", "error", err,
+			"subscription_id", subscriptionID)
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrNotFound("subscription not found")
+	}
+	changes, err := s.subscriptionRepo.GetListOfPriceChanges(ctx, []uuid.UUID{subscriptionID})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get price changes from repository", "layer", "service", "error", err,
+			"subscription_id", subscriptionID)
+		return nil, err
+	}
+	return changes, nil
+}
+
+func (s *Subcription) DeletePriceChangeByID(ctx context.Context, subscriptionID, priceChangeID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "Subcription.DeletePriceChangeByID")
+	defer span.End()
+
+	if err := s.subscriptionRepo.DeletePriceChangeByID(ctx, subscriptionID, priceChangeID); err != nil {
+		slog.ErrorContext(ctx, "failed to delete price change from repository", "layer", "service", "error", err,
+			"price_change_id", priceChangeID)
+		return err
+	}
+	slog.InfoContext(ctx, "price change deleted", "layer", "service", "price_change_id", priceChangeID)
+	return nil
+}
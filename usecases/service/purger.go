@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+)
+
+// Purger permanently removes soft-deleted subscriptions once they are older than the retention.
+type Purger struct {
+	subscriptionRepo repository.SubscriptionDB
+	retention        time.Duration
+	interval         time.Duration
+}
+
+func NewPurger(subsRepo repository.SubscriptionDB, retention, interval time.Duration) *Purger {
+	return &Purger{subscriptionRepo: subsRepo, retention: retention, interval: interval}
+}
+
+// Run purges on every tick until ctx is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	p.purge(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "purger stopped", "layer", "service")
+			return
+		case <-ticker.C:
+			p.purge(ctx)
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	deletedBefore := time.Now().Add(-p.retention)
+	purged, err := p.subscriptionRepo.PurgeDeletedSubscriptions(ctx, deletedBefore)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to purge deleted subscriptions", "layer", "service", "error", err)
+		return
+	}
+	slog.InfoContext(ctx, "deleted subscriptions purged",
+		"layer", "service",
+		"purged", purged,
+		"deleted_before", deletedBefore)
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/notifier"
+
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+)
+
+// ReminderScheduler notifies about subscriptions that end or renew within the window.
+// Every reminder is claimed in the repository for each channel before it is sent there, so it
+// goes out once per channel even across restarts; a failed send releases the claim of that
+// channel only, and the next run retries it there.
+type ReminderScheduler struct {
+	reminderRepo repository.ReminderDB
+	channels     []notifier.Channel
+	interval     time.Duration
+	window       time.Duration
+}
+
+func NewReminderScheduler(reminderRepo repository.ReminderDB, channels []notifier.Channel,
+	interval time.Duration, windowDays int) *ReminderScheduler {
+	return &ReminderScheduler{
+		reminderRepo: reminderRepo,
+		channels:     channels,
+		interval:     interval,
+		window:       time.Duration(windowDays) * 24 * time.Hour,
+	}
+}
+
+// Run checks for due reminders on every tick until ctx is cancelled.
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.remind(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "reminder scheduler stopped", "layer", "service")
+			return
+		case <-ticker.C:
+			s.remind(ctx)
+		}
+	}
+}
+
+func (s *ReminderScheduler) remind(ctx context.Context) {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	windowEnd := today.Add(s.window)
+
+	reminders, err := s.dueReminders(ctx, today, windowEnd)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to find due reminders", "layer", "service", "error", err)
+		return
+	}
+
+	var sent int
+	for i := range reminders {
+		for _, channel := range s.channels {
+			if ctx.Err() != nil {
+				return
+			}
+			if s.send(ctx, &reminders[i], channel) {
+				sent++
+			}
+		}
+	}
+	slog.InfoContext(ctx, "reminders processed", "layer", "service", "due", len(reminders), "sent", sent)
+}
+
+func (s *ReminderScheduler) dueReminders(ctx context.Context, today, windowEnd time.Time) ([]domain.Reminder, error) {
+	var reminders []domain.Reminder
+
+	// end_date is the first day of the last paid month.
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	ending, err := s.reminderRepo.GetSubscriptionsEndingBetween(ctx, monthStart, windowEnd)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range ending {
+		due := domain.LastActiveDay(*sub.EndDate)
+		if due.Before(today) || due.After(windowEnd) {
+			continue
+		}
+		reminders = append(reminders, domain.Reminder{Subscription: sub, Kind: domain.ReminderKindExpiry, DueDate: due})
+	}
+
+	openEnded, err := s.reminderRepo.GetOpenEndedSubscriptions(ctx, windowEnd)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range openEnded {
+		due, ok := sub.NextRenewal(today)
+		if !ok || due.After(windowEnd) {
+			continue
+		}
+		reminders = append(reminders, domain.Reminder{Subscription: sub, Kind: domain.ReminderKindRenewal, DueDate: due})
+	}
+	return reminders, nil
+}
+
+func (s *ReminderScheduler) send(ctx context.Context, reminder *domain.Reminder, channel notifier.Channel) bool {
+	claimed, err := s.reminderRepo.ClaimReminder(ctx, reminder, channel.Name)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to claim reminder", "layer", "service", "error", err,
+			"subscription_id", reminder.Subscription.SubscriptionID,
+			"channel", channel.Name)
+		return false
+	}
+	if !claimed {
+		return false
+	}
+
+	if err := channel.Notify(ctx, *reminder); err != nil {
+		slog.WarnContext(ctx, "failed to send reminder",
+			"layer", "service",
+			"error", err,
+			"subscription_id", reminder.Subscription.SubscriptionID,
+			"kind", reminder.Kind,
+			"channel", channel.Name)
+		// The claim is released even when the scheduler is stopping, so the reminder is
+		// retried after the restart.
+		if err := s.reminderRepo.ReleaseReminder(context.WithoutCancel(ctx), reminder, channel.Name); err != nil {
+			slog.ErrorContext(ctx, "failed to release reminder", "layer", "service", "error", err,
+				"subscription_id", reminder.Subscription.SubscriptionID,
+				"channel", channel.Name)
+		}
+		return false
+	}
+	return true
+}
+
+// WebhookNotifier turns expiry reminders into subscription.ending_soon webhook deliveries.
+type WebhookNotifier struct {
+	webhookRepo repository.WebhookDB
+}
+
+func NewWebhookNotifier(webhookRepo repository.WebhookDB) *WebhookNotifier {
+	return &WebhookNotifier{webhookRepo: webhookRepo}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, reminder domain.Reminder) error {
+	if reminder.Kind != domain.ReminderKindExpiry {
+		return nil
+	}
+	return emitWebhookEvent(ctx, n.webhookRepo, domain.WebhookEventSubscriptionEndingSoon, &reminder.Subscription)
+}
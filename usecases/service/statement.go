@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/statement"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// amountTolerance is how far a charge may be from the median of its merchant and still
+	// count as the same subscription, e.g. after a small price change.
+	amountTolerance = 0.2
+	// minRegularShare is the share of intervals between charges that must match the cadence.
+	minRegularShare = 0.75
+)
+
+// noiseWords are parts of merchant descriptions that don't tell services apart.
+var noiseWords = map[string]bool{"www": true, "com": true, "net": true, "org": true, "ru": true, "io": true}
+
+type Statement struct {
+	candidateRepo    repository.SubscriptionCandidateDB
+	subscriptionRepo repository.SubscriptionDB
+	transactor       repository.Transactor
+}
+
+func NewStatement(candidateRepo repository.SubscriptionCandidateDB, subsRepo repository.SubscriptionDB,
+	transactor repository.Transactor) *Statement {
+	return &Statement{candidateRepo: candidateRepo, subscriptionRepo: subsRepo, transactor: transactor}
+}
+
+func (s *Statement) ImportStatement(ctx context.Context, userID uuid.UUID, format string,
+	r io.Reader) ([]domain.SubscriptionCandidate, error) {
+	transactions, err := statement.Parse(format, r)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to parse bank statement", "layer", "service", "format", format, "error", err)
+		return nil, domain.ErrBadRequest(fmt.Sprintf("failed to parse statement: %v", err))
+	}
+
+	existing, err := s.subscriptionRepo.GetListOfSubscriptions(ctx, &domain.SubscriptionFilter{UserID: &userID})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get subscriptions of user", "layer", "service", "user_id", userID, "error", err)
+		return nil, err
+	}
+	known := make(map[string]bool, len(existing))
+	for _, sub := range existing {
+		known[merchantKey(sub.ServiceName)] = true
+	}
+	// Merchants already waiting for the user's decision are not proposed again.
+	pending, err := s.candidateRepo.GetListOfSubscriptionCandidates(ctx, userID, domain.CandidateStatusPending)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get pending subscription candidates", "layer", "service", "user_id", userID, "error", err)
+		return nil, err
+	}
+	for _, c := range pending {
+		known[c.Merchant] = true
+	}
+
+	candidates := make([]domain.SubscriptionCandidate, 0)
+	for _, c := range detectRecurringCharges(transactions) {
+		if known[c.Merchant] {
+			continue
+		}
+		c.CandidateID = uuid.New()
+		c.UserID = userID
+		if err := s.candidateRepo.SaveSubscriptionCandidate(ctx, &c); err != nil {
+			slog.ErrorContext(ctx, "failed to save subscription candidate", "layer", "service", "user_id", userID, "error", err)
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+
+	slog.InfoContext(ctx, "bank statement imported",
+		"layer", "service",
+		"user_id", userID,
+		"transactions", len(transactions),
+		"candidates", len(candidates))
+	return candidates, nil
+}
+
+func (s *Statement) GetListOfSubscriptionCandidates(ctx context.Context, userID uuid.UUID,
+	status string) ([]domain.SubscriptionCandidate, error) {
+	if status != "" && status != domain.CandidateStatusPending && status != domain.CandidateStatusConfirmed &&
+		status != domain.CandidateStatusDismissed {
+		slog.ErrorContext(ctx, "unknown candidate status", "layer", "service", "status", status)
+		return nil, domain.ErrBadRequest(fmt.Sprintf("unknown status: %q", status))
+	}
+	candidates, err := s.candidateRepo.GetListOfSubscriptionCandidates(ctx, userID, status)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get subscription candidates", "layer", "service", "user_id", userID, "error", err)
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// ConfirmSubscriptionCandidate claims the candidate and creates its subscription in one
+// transaction, so a candidate yields at most one subscription even when confirmed twice at once.
+func (s *Statement) ConfirmSubscriptionCandidate(ctx context.Context, candidateID uuid.UUID,
+	overrides *domain.Subscription) (uuid.UUID, error) {
+	var subscriptionID uuid.UUID
+	err := s.transactor.InTransaction(ctx, func(tx repository.TxDB) error {
+		candidate, err := tx.ClaimSubscriptionCandidate(ctx, candidateID, domain.CandidateStatusConfirmed)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to claim subscription candidate", "layer", "service", "candidate_id", candidateID, "error", err)
+			return err
+		}
+
+		subs := &domain.Subscription{
+			ServiceName:   candidate.ServiceName,
+			Price:         candidate.Price,
+			UserID:        candidate.UserID,
+			StartDate:     candidate.StartDate,
+			BillingPeriod: candidate.BillingPeriod,
+		}
+		if overrides != nil {
+			if overrides.ServiceName != "" {
+				subs.ServiceName = overrides.ServiceName
+			}
+			if overrides.Price != 0 {
+				subs.Price = overrides.Price
+			}
+			if !overrides.StartDate.IsZero() {
+				subs.StartDate = overrides.StartDate
+			}
+			if overrides.BillingPeriod != "" {
+				subs.BillingPeriod = overrides.BillingPeriod
+			}
+		}
+
+		subscriptionID, err = NewSubscription(tx, tx).CreateSubscription(ctx, subs)
+		if err != nil {
+			return err
+		}
+		if err := tx.SetSubscriptionCandidateSubscription(ctx, candidateID, subscriptionID); err != nil {
+			slog.ErrorContext(ctx, "failed to link subscription candidate",
+				"layer", "service",
+				"candidate_id", candidateID,
+				"subscription_id", subscriptionID,
+				"error", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	slog.InfoContext(ctx, "subscription candidate confirmed",
+		"layer", "service",
+		"candidate_id", candidateID,
+		"subscription_id", subscriptionID)
+	return subscriptionID, nil
+}
+
+func (s *Statement) DismissSubscriptionCandidate(ctx context.Context, candidateID uuid.UUID) error {
+	if _, err := s.candidateRepo.ClaimSubscriptionCandidate(ctx, candidateID, domain.CandidateStatusDismissed); err != nil {
+		slog.ErrorContext(ctx, "failed to dismiss subscription candidate", "layer", "service", "candidate_id", candidateID, "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "subscription candidate dismissed", "layer", "service", "candidate_id", candidateID)
+	return nil
+}
+
+// detectRecurringCharges groups the charges of a statement by merchant and keeps the groups
+// whose amounts are close to each other and which repeat monthly or yearly. Groups whose last
+// charge is well over a period before the end of the statement are treated as cancelled.
+func detectRecurringCharges(transactions []domain.BankTransaction) []domain.SubscriptionCandidate {
+	charges := charges(transactions)
+	var statementEnd time.Time
+	groups := make(map[string][]domain.BankTransaction)
+	for _, t := range charges {
+		if t.Date.After(statementEnd) {
+			statementEnd = t.Date
+		}
+		if key := merchantKey(t.Description); key != "" {
+			groups[key] = append(groups[key], t)
+		}
+	}
+
+	var candidates []domain.SubscriptionCandidate
+	for key, group := range groups {
+		sort.Slice(group, func(i, j int) bool { return group[i].Date.Before(group[j].Date) })
+		group = similarAmounts(group)
+		if len(group) < 2 {
+			continue
+		}
+
+		period, ok := cadence(group)
+		if !ok {
+			continue
+		}
+		last := group[len(group)-1]
+		grace := 45 * 24 * time.Hour
+		if period == domain.BillingPeriodYearly {
+			grace = 400 * 24 * time.Hour
+		}
+		if statementEnd.Sub(last.Date) > grace {
+			continue
+		}
+
+		candidates = append(candidates, domain.SubscriptionCandidate{
+			Merchant:      key,
+			ServiceName:   serviceName(last.Description),
+			Price:         int(math.Round(last.Amount)),
+			BillingPeriod: period,
+			StartDate:     domain.MonthStart(group[0].Date),
+			LastChargedAt: last.Date,
+			Occurrences:   len(group),
+			Status:        domain.CandidateStatusPending,
+		})
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Merchant < candidates[j].Merchant })
+	return candidates
+}
+
+// charges returns the outgoing payments as positive amounts. Banks usually export them as
+// negative numbers; a statement without any negative amount is taken to list charges only.
+func charges(transactions []domain.BankTransaction) []domain.BankTransaction {
+	var result []domain.BankTransaction
+	for _, t := range transactions {
+		if t.Amount < 0 {
+			t.Amount = -t.Amount
+			result = append(result, t)
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+	for _, t := range transactions {
+		if t.Amount > 0 {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+func similarAmounts(group []domain.BankTransaction) []domain.BankTransaction {
+	amounts := make([]float64, len(group))
+	for i, t := range group {
+		amounts[i] = t.Amount
+	}
+	sort.Float64s(amounts)
+	median := amounts[len(amounts)/2]
+
+	var result []domain.BankTransaction
+	for _, t := range group {
+		if math.Abs(t.Amount-median) <= median*amountTolerance {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// cadence reports the billing period of charges sorted by date: monthly needs at least
+// three charges mostly 26-35 days apart, yearly at least two charges 350-380 days apart.
+func cadence(group []domain.BankTransaction) (string, bool) {
+	var monthly, yearly int
+	intervals := len(group) - 1
+	for i := 1; i < len(group); i++ {
+		days := group[i].Date.Sub(group[i-1].Date).Hours() / 24
+		switch {
+		case days >= 26 && days <= 35:
+			monthly++
+		case days >= 350 && days <= 380:
+			yearly++
+		}
+	}
+	if intervals >= 2 && float64(monthly) >= float64(intervals)*minRegularShare {
+		return domain.BillingPeriodMonthly, true
+	}
+	if yearly == intervals {
+		return domain.BillingPeriodYearly, true
+	}
+	return "", false
+}
+
+// merchantKey normalizes a statement description so that charges of one merchant match
+// despite card numbers, order IDs and similar noise: words with digits are dropped and at
+// most the first three remaining words are kept, lowercased.
+func merchantKey(description string) string {
+	words := descriptionWords(description)
+	if len(words) > 3 {
+		words = words[:3]
+	}
+	return strings.ToLower(strings.Join(words, " "))
+}
+
+func serviceName(description string) string {
+	words := descriptionWords(description)
+	if len(words) > 3 {
+		words = words[:3]
+	}
+	return strings.Join(words, " ")
+}
+
+func descriptionWords(description string) []string {
+	fields := strings.FieldsFunc(description, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if strings.IndexFunc(f, unicode.IsDigit) >= 0 || noiseWords[strings.ToLower(f)] {
+			continue
+		}
+		words = append(words, f)
+	}
+	return words
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+)
+
+// charged returns a charge of amount on each of the given dates in 2025.
+func charged(description string, amount float64, dates ...string) []domain.BankTransaction {
+	transactions := make([]domain.BankTransaction, len(dates))
+	for i, d := range dates {
+		date, err := time.Parse(time.DateOnly, "2025-"+d)
+		if err != nil {
+			panic(err)
+		}
+		transactions[i] = domain.BankTransaction{Date: date, Description: description, Amount: amount}
+	}
+	return transactions
+}
+
+func TestDetectRecurringCharges(t *testing.T) {
+	tests := []struct {
+		name         string
+		transactions [][]domain.BankTransaction
+		want         []string // merchant, period, price and occurrences of each candidate
+	}{
+		{
+			name: "monthly with card noise in the description",
+			transactions: [][]domain.BankTransaction{
+				charged("NETFLIX.COM 4829", -15.49, "01-05", "02-05"),
+				charged("NETFLIX.COM 5531", -15.49, "03-05", "04-05"),
+			},
+			want: []string{"netflix monthly 15 4"},
+		},
+		{
+			name: "yearly",
+			transactions: [][]domain.BankTransaction{
+				{{Date: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Description: "JetBrains", Amount: -249}},
+				charged("JetBrains", -249, "03-01"),
+			},
+			want: []string{"jetbrains yearly 249 2"},
+		},
+		{
+			name:         "two monthly charges are not enough",
+			transactions: [][]domain.BankTransaction{charged("Spotify", -9.99, "01-10", "02-10")},
+		},
+		{
+			name:         "irregular charges",
+			transactions: [][]domain.BankTransaction{charged("Lavka", -12, "01-03", "01-09", "02-20", "04-01")},
+		},
+		{
+			name: "cancelled long before the statement ends",
+			transactions: [][]domain.BankTransaction{
+				charged("Spotify", -9.99, "01-10", "02-10", "03-10"),
+				charged("Coffee", -4, "07-01"),
+			},
+		},
+		{
+			name: "price outlier is left out",
+			transactions: [][]domain.BankTransaction{
+				charged("Kinopoisk", -299, "01-15", "02-15", "03-15"),
+				charged("Kinopoisk", -2990, "02-20"),
+			},
+			want: []string{"kinopoisk monthly 299 3"},
+		},
+		{
+			name: "income is not a charge",
+			transactions: [][]domain.BankTransaction{
+				charged("Salary", 2500, "01-25", "02-25", "03-25"),
+				charged("Spotify", -9.99, "01-10", "02-10", "03-10"),
+			},
+			want: []string{"spotify monthly 10 3"},
+		},
+		{
+			name:         "statement listing charges as positive amounts",
+			transactions: [][]domain.BankTransaction{charged("Spotify", 9.99, "01-10", "02-10", "03-10")},
+			want:         []string{"spotify monthly 10 3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var transactions []domain.BankTransaction
+			for _, part := range tt.transactions {
+				transactions = append(transactions, part...)
+			}
+			candidates := detectRecurringCharges(transactions)
+
+			got := make([]string, len(candidates))
+			for i, c := range candidates {
+				got[i] = fmt.Sprintf("%s %s %d %d", c.Merchant, c.BillingPeriod, c.Price, c.Occurrences)
+				if c.Status != domain.CandidateStatusPending || c.StartDate.Day() != 1 {
+					t.Errorf("candidate %s: status %q, start %v; want pending from a month start",
+						c.Merchant, c.Status, c.StartDate)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Fatalf("got %v; want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/metrics"
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+)
+
+// StatsCollector periodically refreshes the business gauges, so scrapes never hit the database.
+type StatsCollector struct {
+	statsRepo repository.StatsDB
+	interval  time.Duration
+}
+
+func NewStatsCollector(statsRepo repository.StatsDB, interval time.Duration) *StatsCollector {
+	return &StatsCollector{statsRepo: statsRepo, interval: interval}
+}
+
+// Run refreshes the gauges on every tick until ctx is cancelled.
+func (c *StatsCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.collect(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "stats collector stopped", "layer", "service")
+			return
+		case <-ticker.C:
+			c.collect(ctx)
+		}
+	}
+}
+
+func (c *StatsCollector) collect(ctx context.Context) {
+	stats, err := c.statsRepo.GetSubscriptionStats(ctx, time.Now())
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to collect subscription stats", "layer", "service", "error", err)
+		return
+	}
+	metrics.ActiveSubscriptions.Set(float64(stats.ActiveSubscriptions))
+	metrics.ActiveUsers.Set(float64(stats.ActiveUsers))
+	metrics.PendingOutboxMessages.Set(float64(stats.PendingOutboxMessages))
+}
@@ -1,38 +1,49 @@
 package service
 
 import (
+	"context"
 	"log/slog"
 	"time"
 
 	"github.com/kasparovgs/subscription-aggregation-service/domain"
 
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/tracing"
 	"github.com/kasparovgs/subscription-aggregation-service/repository"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Subcription manages subscriptions. The repository records every change in the audit log,
+// the outbox and the webhook deliveries within the transaction that makes it.
 type Subcription struct {
 	subscriptionRepo repository.SubscriptionDB
+	budgetRepo       repository.BudgetDB
 }
 
-func NewSubscription(subsRepo repository.SubscriptionDB) *Subcription {
-	return &Subcription{subscriptionRepo: subsRepo}
+func NewSubscription(subsRepo repository.SubscriptionDB, budgetRepo repository.BudgetDB) *Subcription {
+	return &Subcription{subscriptionRepo: subsRepo, budgetRepo: budgetRepo}
 }
 
-func (s *Subcription) CreateSubscription(subs *domain.Subscription) (uuid.UUID, error) {
+func (s *Subcription) CreateSubscription(ctx context.Context, subs *domain.Subscription) (uuid.UUID, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.CreateSubscription")
+	defer span.End()
+
 	subscriptionID := uuid.New()
 	subs.SubscriptionID = subscriptionID
-	err := s.subscriptionRepo.CreateSubscription(subs)
+	err := s.subscriptionRepo.CreateSubscription(ctx, subs)
 	if err != nil {
-		slog.Error("failed to create subscription in repository",
+		slog.ErrorContext(ctx, "failed to create subscription in repository",
 			"error", err,
 			"user_id", subs.UserID,
 			"service_name", subs.ServiceName,
 		)
 		return uuid.Nil, err
 	}
+	evaluateBudgets(ctx, s.budgetRepo, s.subscriptionRepo, subs.UserID, time.Now())
 
-	slog.Info("subscription created",
+	slog.InfoContext(ctx, "subscription created",
 		"layer", "service",
 		"subscription_id", subscriptionID,
 		"user_id", subs.UserID,
@@ -41,17 +52,20 @@ func (s *Subcription) CreateSubscription(subs *domain.Subscription) (uuid.UUID,
 	return subscriptionID, nil
 }
 
-func (s *Subcription) GetSubscriptionByID(subscriptionID uuid.UUID) (*domain.Subscription, error) {
-	subs, err := s.subscriptionRepo.GetSubscriptionByID(subscriptionID)
+func (s *Subcription) GetSubscriptionByID(ctx context.Context, subscriptionID uuid.UUID) (*domain.Subscription, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.GetSubscriptionByID")
+	defer span.End()
+
+	subs, err := s.subscriptionRepo.GetSubscriptionByID(ctx, subscriptionID)
 	if err != nil {
-		slog.Error("failed to get subscription from repository",
+		slog.ErrorContext(ctx, "failed to get subscription from repository",
 			"error", err,
 			"subscription_id", subscriptionID,
 		)
 		return nil, err
 	}
 
-	slog.Info("subscription received from repo",
+	slog.InfoContext(ctx, "subscription received from repo",
 		"layer", "service",
 		"subscription_id", subscriptionID,
 		"user_id", subs.UserID,
@@ -60,18 +74,90 @@ func (s *Subcription) GetSubscriptionByID(subscriptionID uuid.UUID) (*domain.Sub
 	return subs, nil
 }
 
-func (s *Subcription) PatchSubscriptionByID(subs *domain.Subscription) (*domain.Subscription, error) {
-	err := s.subscriptionRepo.PatchSubscriptionByID(subs)
+func (s *Subcription) PatchSubscriptionByID(ctx context.Context, subs *domain.Subscription) (*domain.Subscription, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.PatchSubscriptionByID")
+	defer span.End()
+
+	before, err := s.subscriptionRepo.GetSubscriptionByID(ctx, subs.SubscriptionID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get subscription to patch from repository",
+			"error", err,
+			"subscription_id", subs.SubscriptionID,
+		)
+		return nil, err
+	}
+	err = s.subscriptionRepo.PatchSubscriptionByID(ctx, subs)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to patch subscription in repository",
+			"error", err,
+			"subscription_id", subs.SubscriptionID,
+		)
+		return nil, err
+	}
+
+	subscriptionID := subs.SubscriptionID
+	subs, err = s.subscriptionRepo.GetSubscriptionByID(ctx, subscriptionID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get patched subscription from repository",
+			"error", err,
+			"subscription_id", subscriptionID,
+		)
+		return nil, err
+	}
+	s.onSubscriptionUpdated(ctx, before, subs)
+
+	slog.InfoContext(ctx, "subscription patched in repo",
+		"layer", "service",
+		"subscription_id", subs.SubscriptionID,
+		"user_id", subs.UserID,
+		"service_name", subs.ServiceName)
+	return subs, nil
+}
+
+func (s *Subcription) ReplaceSubscription(ctx context.Context, subs *domain.Subscription) (*domain.Subscription, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.ReplaceSubscription")
+	defer span.End()
+
+	before, err := s.subscriptionRepo.GetSubscriptionByID(ctx, subs.SubscriptionID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get subscription to replace from repository",
+			"error", err,
+			"subscription_id", subs.SubscriptionID,
+		)
+		return nil, err
+	}
+	err = s.subscriptionRepo.ReplaceSubscription(ctx, subs)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to replace subscription in repository",
+			"error", err,
+			"subscription_id", subs.SubscriptionID,
+		)
+		return nil, err
+	}
+	s.onSubscriptionUpdated(ctx, before, subs)
+
+	slog.InfoContext(ctx, "subscription replaced in repo",
+		"layer", "service",
+		"subscription_id", subs.SubscriptionID,
+		"user_id", subs.UserID,
+		"service_name", subs.ServiceName)
+	return subs, nil
+}
+
+func (s *Subcription) DeleteSubscriptionByID(ctx context.Context, subs *domain.Subscription) (*domain.Subscription, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.DeleteSubscriptionByID")
+	defer span.End()
+
+	err := s.subscriptionRepo.DeleteSubscriptionByID(ctx, subs)
 	if err != nil {
-		slog.Error("failed to patch subscription in repository",
+		slog.ErrorContext(ctx, "failed to delete subscription from repository",
 			"error", err,
 			"subscription_id", subs.SubscriptionID,
 		)
 		return nil, err
 	}
 
-	subs, _ = s.subscriptionRepo.GetSubscriptionByID(subs.SubscriptionID)
-	slog.Info("subscription patched in repo",
+	slog.InfoContext(ctx, "subscription deleted from repo",
 		"layer", "service",
 		"subscription_id", subs.SubscriptionID,
 		"user_id", subs.UserID,
@@ -79,16 +165,20 @@ func (s *Subcription) PatchSubscriptionByID(subs *domain.Subscription) (*domain.
 	return subs, nil
 }
 
-func (s *Subcription) DeleteSubscriptionByID(subs *domain.Subscription) (*domain.Subscription, error) {
-	err := s.subscriptionRepo.DeleteSubscriptionByID(subs)
+func (s *Subcription) RestoreSubscriptionByID(ctx context.Context, subs *domain.Subscription) (*domain.Subscription, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.RestoreSubscriptionByID")
+	defer span.End()
+
+	err := s.subscriptionRepo.RestoreSubscriptionByID(ctx, subs)
 	if err != nil {
-		slog.Error("failed to delete subscription from repository",
+		slog.ErrorContext(ctx, "failed to restore subscription in repository",
 			"error", err,
 			"subscription_id", subs.SubscriptionID,
 		)
 		return nil, err
 	}
-	slog.Info("subscription deleted from repo",
+
+	slog.InfoContext(ctx, "subscription restored in repo",
 		"layer", "service",
 		"subscription_id", subs.SubscriptionID,
 		"user_id", subs.UserID,
@@ -96,52 +186,110 @@ func (s *Subcription) DeleteSubscriptionByID(subs *domain.Subscription) (*domain
 	return subs, nil
 }
 
-func (s *Subcription) GetListOfSubscriptions(filter *domain.SubscriptionFilter) ([]domain.Subscription, error) {
+func (s *Subcription) onSubscriptionUpdated(ctx context.Context, before, after *domain.Subscription) {
+	evaluateBudgets(ctx, s.budgetRepo, s.subscriptionRepo, after.UserID, time.Now())
+	if before != nil && before.UserID != after.UserID {
+		evaluateBudgets(ctx, s.budgetRepo, s.subscriptionRepo, before.UserID, time.Now())
+	}
+}
+
+func (s *Subcription) GetListOfSubscriptions(ctx context.Context, filter *domain.SubscriptionFilter) ([]domain.Subscription, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.GetListOfSubscriptions")
+	defer span.End()
+
 	if filter == nil {
-		slog.Error("failed to get list by nil filter")
+		slog.ErrorContext(ctx, "failed to get list by nil filter")
 		return nil, domain.ErrBadRequest("failed to get list by nil filter")
 	}
 	if filter.StartDate != nil && filter.EndDate != nil &&
 		filter.StartDate.After(*filter.EndDate) {
-		slog.Error("start date cannot be after end date", "layer", "service")
+		slog.ErrorContext(ctx, "start date cannot be after end date", "layer", "service")
 		return nil, domain.ErrBadRequest("start date cannot be after end date")
 	}
-	list, err := s.subscriptionRepo.GetListOfSubscriptions(filter)
+	list, err := s.subscriptionRepo.GetListOfSubscriptions(ctx, filter)
 	if err != nil {
-		slog.Error("failed to get list of subscriptions by filter", "layer", "service", "error", err)
+		slog.ErrorContext(ctx, "failed to get list of subscriptions by filter", "layer", "service", "error", err)
 		return nil, err
 	}
-	slog.Info("list of subscriptions by filter successfully found", "layer", "service")
+	slog.InfoContext(ctx, "list of subscriptions by filter successfully found", "layer", "service")
 	return list, nil
 }
 
-func (s *Subcription) GetTotalCost(filter *domain.TotalCostFilter) (int, error) {
+func (s *Subcription) ExportSubscriptions(ctx context.Context, filter *domain.SubscriptionFilter,
+	fn func(domain.Subscription) error) error {
+	ctx, span := tracing.Start(ctx, "Subcription.ExportSubscriptions")
+	defer span.End()
+
+	if filter == nil {
+		slog.ErrorContext(ctx, "failed to export by nil filter")
+		return domain.ErrBadRequest("failed to export by nil filter")
+	}
+	if filter.StartDate != nil && filter.EndDate != nil &&
+		filter.StartDate.After(*filter.EndDate) {
+		slog.ErrorContext(ctx, "start date cannot be after end date", "layer", "service")
+		return domain.ErrBadRequest("start date cannot be after end date")
+	}
+	var exported int
+	err := s.subscriptionRepo.ExportSubscriptions(ctx, filter, func(sub domain.Subscription) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		exported++
+		return fn(sub)
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to export subscriptions", "layer", "service", "exported", exported, "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "subscriptions successfully exported", "layer", "service", "exported", exported)
+	return nil
+}
+
+func (s *Subcription) GetTotalCost(ctx context.Context, filter *domain.TotalCostFilter) (int, error) {
+	ctx, span := tracing.Start(ctx, "Subcription.GetTotalCost")
+	defer span.End()
+
 	if filter == nil {
-		slog.Error("failed to get total cost by nil filter")
+		slog.ErrorContext(ctx, "failed to get total cost by nil filter")
 		return 0, domain.ErrBadRequest("failed to get list by nil filter")
 	}
 	if filter.StartDate.After(filter.EndDate) {
-		slog.Error("start date cannot be after end date", "layer", "service")
+		slog.ErrorContext(ctx, "start date cannot be after end date", "layer", "service")
 		return 0, domain.ErrBadRequest("start date cannot be after end date")
 	}
-	subs, err := s.subscriptionRepo.GetTotalCost(filter)
+	totalCost, err := totalCost(ctx, s.subscriptionRepo, filter)
 	if err != nil {
-		slog.Error("failed to get total cost of subscriptions by filter", "layer", "service", "error", err)
+		slog.ErrorContext(ctx, "failed to get total cost of subscriptions by filter", "layer", "service", "error", err)
 		return 0, err
 	}
-
-	var totalCost int
-	for _, sub := range subs {
-		totalCost += costForPeriod(&sub, filter.StartDate, filter.EndDate)
-	}
-	slog.Info("total cost of subscriptions by filter successfully found",
+	slog.InfoContext(ctx, "total cost of subscriptions by filter successfully found",
 		"layer", "service",
 		"total_cost", totalCost)
 
 	return totalCost, nil
 }
 
-func costForPeriod(sub *domain.Subscription, periodStart, periodEnd time.Time) int {
+// totalCost sums what the subscriptions matching filter cost within its period.
+func totalCost(ctx context.Context, subscriptionRepo repository.SubscriptionDB, filter *domain.TotalCostFilter) (int, error) {
+	subs, err := subscriptionRepo.GetTotalCost(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("subscriptions.count", len(subs)))
+
+	changes, err := priceChangesBySubscription(ctx, subscriptionRepo, subs)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	for _, sub := range subs {
+		total += costForPeriod(&sub, changes[sub.SubscriptionID], filter.StartDate, filter.EndDate)
+	}
+	return total, nil
+}
+
+func costForPeriod(sub *domain.Subscription, changes []domain.PriceChange, periodStart, periodEnd time.Time) int {
 	start := maxTime(sub.StartDate, periodStart)
 
 	var end time.Time
@@ -151,18 +299,32 @@ func costForPeriod(sub *domain.Subscription, periodStart, periodEnd time.Time) i
 		end = periodEnd
 	}
 
-	if end.Before(start) {
-		return 0
+	var cost int
+	for month := domain.MonthStart(start); !month.After(end); month = month.AddDate(0, 1, 0) {
+		cost += sub.ChargeForMonth(month, changes)
 	}
-
-	months := countMonths(start, end)
-	return months * sub.Price
+	return cost
 }
 
-func countMonths(start, end time.Time) int {
-	y1, m1, _ := start.Date()
-	y2, m2, _ := end.Date()
-	return (y2-y1)*12 + int(m2-m1) + 1
+// priceChangesBySubscription loads the scheduled price changes of subs, sorted by EffectiveFrom.
+func priceChangesBySubscription(ctx context.Context, subscriptionRepo repository.SubscriptionDB,
+	subs []domain.Subscription) (map[uuid.UUID][]domain.PriceChange, error) {
+	if len(subs) == 0 {
+		return nil, nil
+	}
+	ids := make([]uuid.UUID, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.SubscriptionID)
+	}
+	changes, err := subscriptionRepo.GetListOfPriceChanges(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	bySubscription := make(map[uuid.UUID][]domain.PriceChange)
+	for _, c := range changes {
+		bySubscription[c.SubscriptionID] = append(bySubscription[c.SubscriptionID], c)
+	}
+	return bySubscription, nil
 }
 
 func maxTime(a, b time.Time) time.Time {
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+
+	"github.com/google/uuid"
+)
+
+type Webhook struct {
+	webhookRepo repository.WebhookDB
+}
+
+func NewWebhook(webhookRepo repository.WebhookDB) *Webhook {
+	return &Webhook{webhookRepo: webhookRepo}
+}
+
+// CreateWebhook registers the endpoint. The returned webhook carries the signing secret,
+// which is generated when the caller did not provide one and is never returned again.
+func (w *Webhook) CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error) {
+	if webhook.Secret == "" {
+		secret, err := newWebhookSecret()
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to generate webhook secret", "layer", "service", "error", err)
+			return nil, err
+		}
+		webhook.Secret = secret
+	}
+	webhook.WebhookID = uuid.New()
+	webhook.Active = true
+	webhook.CreatedAt = time.Now().UTC()
+
+	if err := w.webhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		slog.ErrorContext(ctx, "failed to create webhook in repository", "layer", "service", "error", err, "url", webhook.URL)
+		return nil, err
+	}
+	slog.InfoContext(ctx, "webhook created",
+		"layer", "service",
+		"webhook_id", webhook.WebhookID,
+		"event_types", webhook.EventTypes)
+	return webhook, nil
+}
+
+func (w *Webhook) GetWebhookByID(ctx context.Context, webhookID uuid.UUID) (*domain.Webhook, error) {
+	webhook, err := w.webhookRepo.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get webhook from repository", "layer", "service", "error", err, "webhook_id", webhookID)
+		return nil, err
+	}
+	return webhook, nil
+}
+
+func (w *Webhook) GetListOfWebhooks(ctx context.Context) ([]domain.Webhook, error) {
+	webhooks, err := w.webhookRepo.GetListOfWebhooks(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get list of webhooks", "layer", "service", "error", err)
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (w *Webhook) PatchWebhookByID(ctx context.Context, patch *domain.WebhookPatch) (*domain.Webhook, error) {
+	webhook, err := w.webhookRepo.GetWebhookByID(ctx, patch.WebhookID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get webhook from repository", "layer", "service", "error", err, "webhook_id", patch.WebhookID)
+		return nil, err
+	}
+	if patch.URL != nil {
+		webhook.URL = *patch.URL
+	}
+	if patch.EventTypes != nil {
+		webhook.EventTypes = patch.EventTypes
+	}
+	if patch.Active != nil {
+		webhook.Active = *patch.Active
+	}
+	if err := w.webhookRepo.PatchWebhookByID(ctx, webhook); err != nil {
+		slog.ErrorContext(ctx, "failed to patch webhook in repository", "layer", "service", "error", err, "webhook_id", patch.WebhookID)
+		return nil, err
+	}
+	slog.InfoContext(ctx, "webhook patched", "layer", "service", "webhook_id", webhook.WebhookID)
+	return webhook, nil
+}
+
+func (w *Webhook) DeleteWebhookByID(ctx context.Context, webhookID uuid.UUID) error {
+	if err := w.webhookRepo.DeleteWebhookByID(ctx, webhookID); err != nil {
+		slog.ErrorContext(ctx, "failed to delete webhook from repository", "layer", "service", "error", err, "webhook_id", webhookID)
+		return err
+	}
+	slog.InfoContext(ctx, "webhook deleted", "layer", "service", "webhook_id", webhookID)
+	return nil
+}
+
+func (w *Webhook) GetListOfWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) ([]domain.WebhookDelivery, error) {
+	if _, err := w.webhookRepo.GetWebhookByID(ctx, webhookID); err != nil {
+		return nil, err
+	}
+	deliveries, err := w.webhookRepo.GetListOfWebhookDeliveries(ctx, webhookID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get webhook deliveries", "layer", "service", "error", err, "webhook_id", webhookID)
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RedeliverWebhookDelivery queues a new delivery with the original payload, keeping the old one in the log.
+func (w *Webhook) RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	original, err := w.webhookRepo.GetWebhookDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to get webhook delivery", "layer", "service", "error", err, "delivery_id", deliveryID)
+		return nil, err
+	}
+	if original.WebhookID != webhookID {
+		return nil, domain.ErrNotFound("webhook delivery not found")
+	}
+
+	now := time.Now().UTC()
+	delivery := &domain.WebhookDelivery{
+		DeliveryID:    uuid.New(),
+		WebhookID:     webhookID,
+		EventType:     original.EventType,
+		Payload:       original.Payload,
+		Status:        domain.WebhookDeliveryPending,
+		CreatedAt:     now,
+		NextAttemptAt: now,
+	}
+	if err := w.webhookRepo.CreateWebhookDelivery(ctx, delivery); err != nil {
+		slog.ErrorContext(ctx, "failed to create webhook redelivery", "layer", "service", "error", err, "delivery_id", deliveryID)
+		return nil, err
+	}
+	slog.InfoContext(ctx, "webhook delivery queued for redelivery",
+		"layer", "service",
+		"webhook_id", webhookID,
+		"delivery_id", delivery.DeliveryID,
+		"original_delivery_id", deliveryID)
+	return delivery, nil
+}
+
+// emitWebhookEvent queues a delivery for every webhook subscribed to an event that is not
+// caused by a change of a subscription, such as a reminder. Changes queue their deliveries in
+// their own transaction in the repository.
+func emitWebhookEvent(ctx context.Context, webhookRepo repository.WebhookDB, eventType string,
+	subs *domain.Subscription) error {
+	webhooks, err := webhookRepo.GetListOfWebhooksForEvent(ctx, eventType, subs.UserID)
+	if err != nil {
+		return fmt.Errorf("get webhooks for event: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for _, webhook := range webhooks {
+		deliveryID := uuid.New()
+		payload, err := json.Marshal(domain.WebhookPayload{
+			DeliveryID:   deliveryID,
+			EventType:    eventType,
+			OccurredAt:   now,
+			Subscription: subs,
+		})
+		if err != nil {
+			return err
+		}
+		delivery := &domain.WebhookDelivery{
+			DeliveryID:    deliveryID,
+			WebhookID:     webhook.WebhookID,
+			EventType:     eventType,
+			Payload:       payload,
+			Status:        domain.WebhookDeliveryPending,
+			CreatedAt:     now,
+			NextAttemptAt: now,
+		}
+		if err := webhookRepo.CreateWebhookDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("queue webhook delivery: %w", err)
+		}
+	}
+	return nil
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/webhook"
+
+	"github.com/kasparovgs/subscription-aggregation-service/repository"
+)
+
+// errWebhookUnavailable fails a delivery at once: its webhook was deleted or deactivated
+// after the delivery was queued, so retrying cannot help.
+var errWebhookUnavailable = errors.New("webhook is deleted or inactive")
+
+// WebhookDispatcher sends pending webhook deliveries, retrying failures with exponential backoff
+// until maxAttempts is reached.
+type WebhookDispatcher struct {
+	webhookRepo repository.WebhookDB
+	client      *webhook.Client
+	interval    time.Duration
+	batchSize   int
+	lease       time.Duration
+	maxAttempts int
+	maxBackoff  time.Duration
+}
+
+func NewWebhookDispatcher(webhookRepo repository.WebhookDB, client *webhook.Client, interval time.Duration,
+	batchSize int, lease time.Duration, maxAttempts int, maxBackoff time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhookRepo: webhookRepo,
+		client:      client,
+		interval:    interval,
+		batchSize:   batchSize,
+		lease:       lease,
+		maxAttempts: maxAttempts,
+		maxBackoff:  maxBackoff,
+	}
+}
+
+// Run dispatches on every tick until ctx is cancelled.
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.InfoContext(ctx, "webhook dispatcher stopped", "layer", "service")
+			return
+		case <-ticker.C:
+			d.dispatch(ctx)
+		}
+	}
+}
+
+func (d *WebhookDispatcher) dispatch(ctx context.Context) {
+	deliveries, err := d.webhookRepo.ClaimWebhookDeliveries(ctx, d.batchSize, d.lease)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to claim webhook deliveries", "layer", "service", "error", err)
+		return
+	}
+	for i := range deliveries {
+		d.deliver(ctx, &deliveries[i])
+	}
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	delivery.Attempts++
+	code, err := d.send(ctx, delivery)
+	if code != 0 {
+		delivery.ResponseCode = &code
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case err == nil:
+		delivery.Status = domain.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+	case delivery.Attempts >= d.maxAttempts || errors.Is(err, errWebhookUnavailable):
+		reason := err.Error()
+		delivery.Status = domain.WebhookDeliveryFailed
+		delivery.LastError = &reason
+	default:
+		reason := err.Error()
+		delivery.LastError = &reason
+		delivery.NextAttemptAt = now.Add(backoff(delivery.Attempts, d.interval, d.maxBackoff))
+	}
+
+	if err := d.webhookRepo.UpdateWebhookDelivery(ctx, delivery); err != nil {
+		slog.ErrorContext(ctx, "failed to update webhook delivery", "layer", "service", "error", err, "delivery_id", delivery.DeliveryID)
+		return
+	}
+	slog.InfoContext(ctx, "webhook delivery attempted",
+		"layer", "service",
+		"delivery_id", delivery.DeliveryID,
+		"webhook_id", delivery.WebhookID,
+		"status", delivery.Status,
+		"attempts", delivery.Attempts,
+		"response_code", code)
+}
+
+// send POSTs the delivery to its webhook and returns the response status code, zero when no
+// response was received. Any other status than 2xx is an error.
+func (d *WebhookDispatcher) send(ctx context.Context, delivery *domain.WebhookDelivery) (int, error) {
+	hook, err := d.webhookRepo.GetWebhookByID(ctx, delivery.WebhookID)
+	var myErr *domain.MyErr
+	if errors.As(err, &myErr) && myErr.Code == domain.CodeNotFound {
+		return 0, errWebhookUnavailable
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get webhook: %w", err)
+	}
+	if !hook.Active {
+		return 0, errWebhookUnavailable
+	}
+
+	code, err := d.client.Send(ctx, hook.URL, hook.Secret, delivery.EventType, delivery.DeliveryID.String(), delivery.Payload)
+	if err == nil && (code < 200 || code >= 300) {
+		err = fmt.Errorf("endpoint responded with status %d", code)
+	}
+	return code, err
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+	"github.com/kasparovgs/subscription-aggregation-service/pkg/webhook"
+
+	"github.com/google/uuid"
+)
+
+// memoryWebhookDB keeps webhooks and deliveries in memory. Claiming returns every pending
+// delivery: leases and schedules are the database's job.
+type memoryWebhookDB struct {
+	mu         sync.Mutex
+	webhooks   map[uuid.UUID]domain.Webhook
+	deliveries map[uuid.UUID]domain.WebhookDelivery
+}
+
+func newMemoryWebhookDB() *memoryWebhookDB {
+	return &memoryWebhookDB{
+		webhooks:   make(map[uuid.UUID]domain.Webhook),
+		deliveries: make(map[uuid.UUID]domain.WebhookDelivery),
+	}
+}
+
+func (m *memoryWebhookDB) CreateWebhook(_ context.Context, w *domain.Webhook) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.webhooks[w.WebhookID] = *w
+	return nil
+}
+
+func (m *memoryWebhookDB) GetWebhookByID(_ context.Context, webhookID uuid.UUID) (*domain.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.webhooks[webhookID]
+	if !ok {
+		return nil, domain.ErrNotFound("webhook not found")
+	}
+	return &w, nil
+}
+
+func (m *memoryWebhookDB) GetListOfWebhooks(context.Context) ([]domain.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var webhooks []domain.Webhook
+	for _, w := range m.webhooks {
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, nil
+}
+
+func (m *memoryWebhookDB) GetListOfWebhooksForEvent(_ context.Context, eventType string, userID uuid.UUID) ([]domain.Webhook, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var webhooks []domain.Webhook
+	for _, w := range m.webhooks {
+		if !w.Active || (w.UserID != nil && *w.UserID != userID) {
+			continue
+		}
+		for _, t := range w.EventTypes {
+			if t == eventType {
+				webhooks = append(webhooks, w)
+				break
+			}
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *memoryWebhookDB) PatchWebhookByID(ctx context.Context, w *domain.Webhook) error {
+	return m.CreateWebhook(ctx, w)
+}
+
+func (m *memoryWebhookDB) DeleteWebhookByID(_ context.Context, webhookID uuid.UUID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.webhooks, webhookID)
+	return nil
+}
+
+func (m *memoryWebhookDB) CreateWebhookDelivery(_ context.Context, d *domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries[d.DeliveryID] = *d
+	return nil
+}
+
+func (m *memoryWebhookDB) GetWebhookDeliveryByID(_ context.Context, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	d, ok := m.deliveries[deliveryID]
+	if !ok {
+		return nil, domain.ErrNotFound("webhook delivery not found")
+	}
+	return &d, nil
+}
+
+func (m *memoryWebhookDB) GetListOfWebhookDeliveries(_ context.Context, webhookID uuid.UUID) ([]domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deliveries []domain.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.WebhookID == webhookID {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *memoryWebhookDB) ClaimWebhookDeliveries(_ context.Context, limit int, _ time.Duration) ([]domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deliveries []domain.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.Status == domain.WebhookDeliveryPending && len(deliveries) < limit {
+			deliveries = append(deliveries, d)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *memoryWebhookDB) UpdateWebhookDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	return m.CreateWebhookDelivery(ctx, d)
+}
+
+// queueDelivery registers a webhook for url and queues one delivery to it.
+func queueDelivery(t *testing.T, repo *memoryWebhookDB, url, secret string, active bool) *domain.WebhookDelivery {
+	t.Helper()
+	ctx := context.Background()
+	hook := &domain.Webhook{
+		WebhookID:  uuid.New(),
+		URL:        url,
+		Secret:     secret,
+		EventTypes: []string{domain.WebhookEventSubscriptionCreated},
+		Active:     active,
+	}
+	if err := repo.CreateWebhook(ctx, hook); err != nil {
+		t.Fatal(err)
+	}
+	delivery := &domain.WebhookDelivery{
+		DeliveryID: uuid.New(),
+		WebhookID:  hook.WebhookID,
+		EventType:  domain.WebhookEventSubscriptionCreated,
+		Payload:    []byte(`{"event_type":"subscription.created"}`),
+		Status:     domain.WebhookDeliveryPending,
+	}
+	if err := repo.CreateWebhookDelivery(ctx, delivery); err != nil {
+		t.Fatal(err)
+	}
+	return delivery
+}
+
+func newTestDispatcher(repo *memoryWebhookDB, maxAttempts int) *WebhookDispatcher {
+	return NewWebhookDispatcher(repo, webhook.NewClient(5*time.Second), time.Millisecond, 10, time.Minute,
+		maxAttempts, time.Millisecond)
+}
+
+func getDelivery(t *testing.T, repo *memoryWebhookDB, deliveryID uuid.UUID) *domain.WebhookDelivery {
+	t.Helper()
+	d, err := repo.GetWebhookDeliveryByID(context.Background(), deliveryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestWebhookDispatcherRetriesAfterServerError(t *testing.T) {
+	const secret = "test-secret"
+	var (
+		mu       sync.Mutex
+		requests int
+		failures []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(webhook.HeaderTimestamp), 10, 64)
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if err != nil || !webhook.Verify(secret, timestamp, body, r.Header.Get(webhook.HeaderSignature)) {
+			failures = append(failures, "invalid signature "+r.Header.Get(webhook.HeaderSignature))
+		}
+		if got := r.Header.Get(webhook.HeaderEventType); got != domain.WebhookEventSubscriptionCreated {
+			failures = append(failures, "event type header "+got)
+		}
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := newMemoryWebhookDB()
+	delivery := queueDelivery(t, repo, server.URL, secret, true)
+	dispatcher := newTestDispatcher(repo, 3)
+	ctx := context.Background()
+
+	dispatcher.dispatch(ctx)
+	got := getDelivery(t, repo, delivery.DeliveryID)
+	if got.Status != domain.WebhookDeliveryPending || got.Attempts != 1 {
+		t.Fatalf("after a 503: status %q, attempts %d; want pending, 1", got.Status, got.Attempts)
+	}
+	if got.ResponseCode == nil || *got.ResponseCode != http.StatusServiceUnavailable {
+		t.Fatalf("after a 503: response code %v; want 503", got.ResponseCode)
+	}
+	if got.LastError == nil {
+		t.Fatal("after a 503: no error recorded")
+	}
+
+	dispatcher.dispatch(ctx)
+	got = getDelivery(t, repo, delivery.DeliveryID)
+	if got.Status != domain.WebhookDeliverySucceeded || got.Attempts != 2 {
+		t.Fatalf("after the retry: status %q, attempts %d; want succeeded, 2", got.Status, got.Attempts)
+	}
+	if got.ResponseCode == nil || *got.ResponseCode != http.StatusNoContent {
+		t.Fatalf("after the retry: response code %v; want 204", got.ResponseCode)
+	}
+	if got.DeliveredAt == nil || got.LastError != nil {
+		t.Fatalf("after the retry: delivered at %v, last error %v", got.DeliveredAt, got.LastError)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != 2 {
+		t.Errorf("receiver got %d requests; want 2", requests)
+	}
+	for _, f := range failures {
+		t.Error(f)
+	}
+}
+
+func TestWebhookDispatcherGivesUpAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := newMemoryWebhookDB()
+	delivery := queueDelivery(t, repo, server.URL, "secret", true)
+	dispatcher := newTestDispatcher(repo, 2)
+
+	dispatcher.dispatch(context.Background())
+	dispatcher.dispatch(context.Background())
+	got := getDelivery(t, repo, delivery.DeliveryID)
+	if got.Status != domain.WebhookDeliveryFailed || got.Attempts != 2 {
+		t.Fatalf("status %q, attempts %d; want failed, 2", got.Status, got.Attempts)
+	}
+	if got.ResponseCode == nil || *got.ResponseCode != http.StatusInternalServerError {
+		t.Fatalf("response code %v; want 500", got.ResponseCode)
+	}
+}
+
+func TestWebhookDispatcherFailsDeliveriesOfUnavailableWebhooks(t *testing.T) {
+	var requests int
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	repo := newMemoryWebhookDB()
+	inactive := queueDelivery(t, repo, server.URL, "secret", false)
+	deleted := queueDelivery(t, repo, server.URL, "secret", true)
+	if err := repo.DeleteWebhookByID(context.Background(), deleted.WebhookID); err != nil {
+		t.Fatal(err)
+	}
+
+	newTestDispatcher(repo, 5).dispatch(context.Background())
+	for name, delivery := range map[string]*domain.WebhookDelivery{"inactive": inactive, "deleted": deleted} {
+		got := getDelivery(t, repo, delivery.DeliveryID)
+		if got.Status != domain.WebhookDeliveryFailed || got.Attempts != 1 || got.LastError == nil {
+			t.Errorf("%s webhook: status %q, attempts %d, error %v; want failed after one attempt",
+				name, got.Status, got.Attempts, got.LastError)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if requests != 0 {
+		t.Errorf("receiver got %d requests; want none", requests)
+	}
+}
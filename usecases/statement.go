@@ -0,0 +1,22 @@
+package usecases
+
+import (
+	"context"
+	"io"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+type Statement interface {
+	// ImportStatement reads a bank statement of the user and returns the recurring charges found
+	// in it as pending subscription candidates. Merchants the user already has a subscription or
+	// a pending candidate for are skipped.
+	ImportStatement(ctx context.Context, userID uuid.UUID, format string, r io.Reader) ([]domain.SubscriptionCandidate, error)
+	GetListOfSubscriptionCandidates(ctx context.Context, userID uuid.UUID, status string) ([]domain.SubscriptionCandidate, error)
+	// ConfirmSubscriptionCandidate creates a subscription from a pending candidate. Non-zero fields
+	// of overrides replace the detected values.
+	ConfirmSubscriptionCandidate(ctx context.Context, candidateID uuid.UUID, overrides *domain.Subscription) (uuid.UUID, error)
+	DismissSubscriptionCandidate(ctx context.Context, candidateID uuid.UUID) error
+}
@@ -1,16 +1,27 @@
 package usecases
 
 import (
+	"context"
+
 	"github.com/kasparovgs/subscription-aggregation-service/domain"
 
 	"github.com/google/uuid"
 )
 
 type Subcription interface {
-	CreateSubscription(subs *domain.Subscription) (uuid.UUID, error)
-	GetSubscriptionByID(subscriptionID uuid.UUID) (*domain.Subscription, error)
-	GetListOfSubscriptions(filter *domain.SubscriptionFilter) ([]domain.Subscription, error)
-	GetTotalCost(filter *domain.TotalCostFilter) (int, error)
-	PatchSubscriptionByID(subs *domain.Subscription) (*domain.Subscription, error)
-	DeleteSubscriptionByID(subs *domain.Subscription) (*domain.Subscription, error)
+	CreateSubscription(ctx context.Context, subs *domain.Subscription) (uuid.UUID, error)
+	// ImportSubscriptions creates all subscriptions at once. With dryRun nothing is stored.
+	ImportSubscriptions(ctx context.Context, subs []*domain.Subscription, dryRun bool) ([]uuid.UUID, error)
+	GetSubscriptionByID(ctx context.Context, subscriptionID uuid.UUID) (*domain.Subscription, error)
+	GetListOfSubscriptions(ctx context.Context, filter *domain.SubscriptionFilter) ([]domain.Subscription, error)
+	ExportSubscriptions(ctx context.Context, filter *domain.SubscriptionFilter, fn func(domain.Subscription) error) error
+	GetTotalCost(ctx context.Context, filter *domain.TotalCostFilter) (int, error)
+	PatchSubscriptionByID(ctx context.Context, subs *domain.Subscription) (*domain.Subscription, error)
+	ReplaceSubscription(ctx context.Context, subs *domain.Subscription) (*domain.Subscription, error)
+	DeleteSubscriptionByID(ctx context.Context, subs *domain.Subscription) (*domain.Subscription, error)
+	RestoreSubscriptionByID(ctx context.Context, subs *domain.Subscription) (*domain.Subscription, error)
+	SchedulePriceChange(ctx context.Context, change *domain.PriceChange) (*domain.PriceChange, error)
+	GetListOfPriceChanges(ctx context.Context, subscriptionID uuid.UUID) ([]domain.PriceChange, error)
+	DeletePriceChangeByID(ctx context.Context, subscriptionID, priceChangeID uuid.UUID) error
+	GetForecast(ctx context.Context, filter *domain.ForecastFilter) ([]domain.ForecastMonth, error)
 }
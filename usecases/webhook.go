@@ -0,0 +1,19 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/kasparovgs/subscription-aggregation-service/domain"
+
+	"github.com/google/uuid"
+)
+
+type Webhook interface {
+	CreateWebhook(ctx context.Context, webhook *domain.Webhook) (*domain.Webhook, error)
+	GetWebhookByID(ctx context.Context, webhookID uuid.UUID) (*domain.Webhook, error)
+	GetListOfWebhooks(ctx context.Context) ([]domain.Webhook, error)
+	PatchWebhookByID(ctx context.Context, patch *domain.WebhookPatch) (*domain.Webhook, error)
+	DeleteWebhookByID(ctx context.Context, webhookID uuid.UUID) error
+	GetListOfWebhookDeliveries(ctx context.Context, webhookID uuid.UUID) ([]domain.WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, webhookID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error)
+}